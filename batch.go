@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bufio"
+	"time"
+
+	"github.com/norwd/ddate/discordian"
+	"github.com/norwd/ddate/internal/os"
+)
+
+// batch converts the dates read from stdin, one per line, relative to now, and
+// prints every date rendered on a line of its own.
+//
+// If a line cannot be converted, the program exits with an error naming the
+// line. If keepGoing is set, the error is reported and the remaining lines are
+// converted, and the program exits with an error once all lines are done. The
+// errors are printed in the given output.
+func batch(self string, now time.Time, render func(time.Time) (string, error), keepGoing bool, output outputFlag) {
+	scanner := bufio.NewScanner(os.Stdin)
+	failed := false
+
+	for number := 1; scanner.Scan(); number++ {
+		date, err := discordian.Parse(scanner.Text(), now)
+
+		var line string
+		if err == nil {
+			line, err = render(date)
+		}
+
+		if err != nil && !keepGoing {
+			output.errorf("%s: line %d: %s", self, number, err)
+			return
+		} else if err != nil {
+			output.warnf("%s: line %d: %s", self, number, err)
+			failed = true
+		} else {
+			println(line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		output.errorf("%s: %s", self, err)
+		return
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/norwd/ddate/format"
+	"github.com/norwd/ddate/internal/os"
+)
+
+func TestBatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string   // name of the test case
+		args   []string // arguments to pass to main
+		stdin  string   // lines to read from stdin
+		stdout string   // expected output
+		stderr string   // expected error output
+		exit   int      // expected error code
+	}{
+		{
+			name:   "Dash",
+			args:   []string{"+%{%e of %B%}, %Y", "-"},
+			stdin:  "26 9 1995\n2000-02-29\n@0\n",
+			stdout: "50th of Bureaucracy, 3161\nSt. Tib's Day, 3166\n1st of Chaos, 3136\n",
+		},
+		{
+			name:   "Stdin Flag",
+			args:   []string{"--stdin"},
+			stdin:  " 2026-10-18 \r\n19 10 2026",
+			stdout: "Sweetmorn, Bureaucracy 72, 3192 YOLD\nBoomtime, Bureaucracy 73, 3192 YOLD\n",
+		},
+		{
+			name:   "Bad Line",
+			args:   []string{"-"},
+			stdin:  "26 9 1995\nbogus\n2000-01-01\n",
+			stdout: "Prickle-Prickle, Bureaucracy 50, 3161 YOLD\n",
+			stderr: "ddate: line 2: invalid date \"bogus\"\n",
+			exit:   1,
+		},
+		{
+			name:   "Keep Going",
+			args:   []string{"--keep-going", "-"},
+			stdin:  "26 9 1995\n\n26 IX 1995\n2000-01-01\n",
+			stdout: "Prickle-Prickle, Bureaucracy 50, 3161 YOLD\nSweetmorn, Chaos 1, 3166 YOLD\n",
+			stderr: "ddate: line 2: empty date\nddate: line 3: invalid date \"26 IX 1995\"\n",
+			exit:   1,
+		},
+		{
+			name:   "Strict Backend Failure",
+			args:   []string{"--strict", "--keep-going", "+%A", "-"},
+			stdin:  "2000-02-29\n2000-03-01\n",
+			stdout: "Setting Orange\n",
+			stderr: "ddate: line 1: directive undefined on St. Tib's Day: %A at offset 0\n",
+			exit:   1,
+		},
+		{
+			name:   "NDJSON",
+			args:   []string{"--output", "json", "--keep-going", "+%d", "-"},
+			stdin:  "1 1 2000\nbogus\n29 2 2000\n",
+			stdout: `{"gregorian":"2000-01-01","yold":3166,"season":"Chaos","day":1,"weekday":"Sweetmorn","tibs_day":false,"x_day":2433066,"formatted":"1"}` + "\n" + `{"gregorian":"2000-02-29","yold":3166,"tibs_day":true,"x_day":2433007,"formatted":""}` + "\n",
+			stderr: `{"error":"ddate: line 2: invalid date \"bogus\""}` + "\n",
+			exit:   1,
+		},
+		{
+			name:   "Dash And Date",
+			args:   []string{"--date", "today", "-"},
+			stderr: "ddate: cannot use both --stdin and a date\n",
+			exit:   1,
+		},
+	}
+
+	for _, test := range tests {
+		// shadow loop var to prevent nasty bugs
+		test := test
+
+		// trim whitespace from name of test case
+		name := strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+
+			return r
+		}, test.name)
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			var errBuf, outBuf bytes.Buffer
+			var exit int
+
+			defer os.MockAndLockStdin(strings.NewReader(test.stdin)).Unlock()
+			defer os.MockAndLockStderr(&errBuf).Unlock()
+			defer os.MockAndLockStdout(&outBuf).Unlock()
+			defer os.MockAndLockArgs("ddate", append([]string{"--utc"}, test.args...)).Unlock()
+			defer os.MockAndLockEnv(nil).Unlock()
+			defer os.MockAndLockFS(nil).Unlock()
+			defer mockAndLockBackend(format.Format).Unlock()
+			defer os.MockAndLockExit(func(code int) {
+				exit = code
+
+				// simulate exit
+				panic("exit")
+			}).Unlock()
+
+			// Act
+			func() {
+				// catch "fake" panic
+				defer func() {
+					if err := recover(); err != nil && err != "exit" {
+						panic(err)
+					}
+				}()
+
+				main()
+			}()
+
+			// Assert
+			if have, want := outBuf.String(), test.stdout; have != want {
+				t.Errorf("stdout: have %q, want %q", have, want)
+			}
+
+			if have, want := errBuf.String(), test.stderr; have != want {
+				t.Errorf("stderr: have %q, want %q", have, want)
+			}
+
+			if have, want := exit, test.exit; have != want {
+				t.Errorf("exit code: have %d, want %d", have, want)
+			}
+		})
+	}
+}
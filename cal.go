@@ -0,0 +1,264 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/norwd/ddate/format"
+)
+
+const (
+	// cellWidth is the width of a single day in a calendar grid.
+	cellWidth = 4
+
+	// seasonWidth is the width of the calendar grid of a single season.
+	seasonWidth = cellWidth * format.DaysPerWeek
+
+	// columnGap is the space between seasons printed side by side.
+	columnGap = "  "
+
+	// seasonsPerRow is the number of seasons printed side by side for a YOLD.
+	seasonsPerRow = 3
+)
+
+// calendarUsage describes the arguments of the cal command.
+var calendarUsage = usage{
+	args:    "[-y | -3] [<Season>] [<YOLD>]",
+	summary: "Print a calendar of the current season, or of the given season or YOLD.",
+}
+
+// calendar prints a cal(1)-style calendar of Discordian seasons, with today
+// highlighted. By default, the season of today is printed. The arguments may
+// name another season and YOLD, and options select a whole YOLD (-y) or the
+// seasons around the season (-3).
+func calendar(self string, args []string) {
+	var s settings
+
+	flags := newFlagSet(self, "cal", calendarUsage)
+	s.register(flags)
+
+	year := flags.Bool("y", false, "print every season of the YOLD")
+	three := flags.Bool("3", false, "print the previous, current, and next season")
+
+	args, ok := parseFlags(self, flags, args)
+	if !ok {
+		return
+	}
+
+	if err := s.resolve(); err != nil {
+		errorf("%s: %s", self, err)
+		return
+	}
+
+	today, locale := format.NewDate(s.today), s.locale
+
+	season, yold := today.Season, today.YOLD
+
+	switch len(args) {
+	case 0:
+		// print the current season
+	case 1:
+		if n, err := strconv.Atoi(args[0]); err == nil {
+			// a lone YOLD prints the whole YOLD, like cal(1) does
+			yold, *year = n, true
+		} else if season, err = format.ParseSeason(args[0]); err != nil {
+			errorf("%s: %s", self, err)
+			return
+		}
+	case 2:
+		var err error
+
+		if season, err = format.ParseSeason(args[0]); err != nil {
+			errorf("%s: %s", self, err)
+			return
+		}
+
+		if yold, err = strconv.Atoi(args[1]); err != nil {
+			errorf("%s: invalid YOLD %q", self, args[1])
+			return
+		}
+	default:
+		errorf("%s: too many arguments for calendar", self)
+		return
+	}
+
+	var lines []string
+
+	switch {
+	case *year:
+		lines = append(lines, center(strconv.Itoa(yold), seasonsPerRow*seasonWidth+(seasonsPerRow-1)*len(columnGap)), "")
+
+		for first := format.Chaos; first <= format.TheAftermath; first += seasonsPerRow {
+			var blocks [][]string
+
+			for season := first; season < first+seasonsPerRow && season <= format.TheAftermath; season++ {
+				blocks = append(blocks, renderSeason(yold, season, locale.Season(season), today, locale))
+			}
+
+			if first > format.Chaos {
+				lines = append(lines, "")
+			}
+
+			lines = append(lines, sideBySide(blocks)...)
+		}
+	case *three:
+		var blocks [][]string
+
+		for offset := -1; offset <= 1; offset++ {
+			season, yold := shiftSeason(season, yold, offset)
+			title := locale.Season(season) + " " + strconv.Itoa(yold)
+
+			blocks = append(blocks, renderSeason(yold, season, title, today, locale))
+		}
+
+		lines = sideBySide(blocks)
+	default:
+		title := locale.Season(season) + " " + strconv.Itoa(yold)
+		lines = renderSeason(yold, season, title, today, locale)
+	}
+
+	for _, line := range lines {
+		println(strings.TrimRight(line, " "))
+	}
+}
+
+// shiftSeason returns the season offset by the given number of seasons from the
+// given season and YOLD, wrapping around into the previous or next YOLD.
+func shiftSeason(season format.Season, yold, offset int) (format.Season, int) {
+	const seasons = int(format.TheAftermath) + 1
+
+	n := yold*seasons + int(season) + offset
+
+	// use floored division, so that negative YOLDs wrap correctly
+	yold, index := n/seasons, n%seasons
+	if index < 0 {
+		yold, index = yold-1, index+seasons
+	}
+
+	return format.Season(index), yold
+}
+
+// renderSeason returns the lines of the calendar grid of a season in a YOLD,
+// headed by the given title. Holydays are marked with an asterisk and today is
+// enclosed in brackets. In YOLDs with a St. Tib's Day, it is printed on a line
+// of its own between Chaos 59 and Chaos 60.
+func renderSeason(yold int, season format.Season, title string, today format.Date, locale *format.Locale) []string {
+	lines := []string{center(title, seasonWidth)}
+
+	var row strings.Builder
+
+	for weekday := format.Sweetmorn; weekday <= format.SettingOrange; weekday++ {
+		fmt.Fprintf(&row, " %2s ", locale.WeekdayAbbr(weekday))
+	}
+
+	lines = append(lines, row.String())
+	row.Reset()
+
+	// flush appends the current week to the lines, padded to the full width.
+	flush := func() {
+		lines = append(lines, pad(row.String(), seasonWidth))
+		row.Reset()
+	}
+
+	for day := 1; day <= format.DaysPerSeason; day++ {
+		date := format.DateOf(yold, season, day)
+
+		// indent the first week, and the week continuing after St. Tib's Day
+		if row.Len() == 0 {
+			row.WriteString(strings.Repeat(" ", cellWidth*int(date.Weekday)))
+		}
+
+		left, right := ' ', ' '
+
+		if _, ok := date.Holyday(); ok {
+			right = '*'
+		}
+
+		if date.YOLD == today.YOLD && date.Season == today.Season && date.Day == today.Day && !today.TibsDay {
+			left, right = '[', ']'
+		}
+
+		fmt.Fprintf(&row, "%c%2d%c", left, day, right)
+
+		if date.Weekday == format.SettingOrange {
+			flush()
+		}
+
+		if season == format.Chaos && day == 59 && hasTibsDay(yold) {
+			if row.Len() > 0 {
+				flush()
+			}
+
+			name := locale.TibsDay
+			if today.TibsDay && today.YOLD == yold {
+				name = "[" + name + "]"
+			}
+
+			lines = append(lines, center(name, seasonWidth))
+		}
+	}
+
+	if row.Len() > 0 {
+		flush()
+	}
+
+	return lines
+}
+
+// hasTibsDay reports whether there is a St. Tib's Day in the YOLD.
+func hasTibsDay(yold int) bool {
+	_, err := format.Date{YOLD: yold, TibsDay: true}.Time(time.UTC)
+
+	return err == nil
+}
+
+// sideBySide places blocks of lines next to each other, separated by a gap.
+// Every block is expected to be one season wide.
+func sideBySide(blocks [][]string) []string {
+	height := 0
+
+	for _, block := range blocks {
+		if len(block) > height {
+			height = len(block)
+		}
+	}
+
+	lines := make([]string, height)
+
+	for i, block := range blocks {
+		for j := range lines {
+			if i > 0 {
+				lines[j] += columnGap
+			}
+
+			if j < len(block) {
+				lines[j] += pad(block[j], seasonWidth)
+			} else {
+				lines[j] += strings.Repeat(" ", seasonWidth)
+			}
+		}
+	}
+
+	return lines
+}
+
+// center centers the text within the given width.
+func center(text string, width int) string {
+	if n := utf8.RuneCountInString(text); n < width {
+		return pad(strings.Repeat(" ", (width-n)/2)+text, width)
+	}
+
+	return text
+}
+
+// pad pads the text with spaces to the given width.
+func pad(text string, width int) string {
+	if n := utf8.RuneCountInString(text); n < width {
+		return text + strings.Repeat(" ", width-n)
+	}
+
+	return text
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/norwd/ddate/internal/os"
+)
+
+func TestCalendar(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string   // name of the test case
+		args   []string // arguments to pass to the calendar
+		today  string   // date to highlight
+		locale string   // locale of the names
+		want   []string // expected lines of output, in order
+		exit   int      // expected error code
+	}{
+		{
+			name:   "Current Season",
+			args:   []string{},
+			today:  "2026-10-18",
+			locale: "en",
+			want: []string{
+				"  Bureaucracy 3192",
+				" SM  BT  PD  PP  SO",
+				"                  1",
+				"  2   3   4   5*  6",
+				"  7   8   9  10  11",
+				" 12  13  14  15  16",
+				" 17  18  19  20  21",
+				" 22  23  24  25  26",
+				" 27  28  29  30  31",
+				" 32  33  34  35  36",
+				" 37  38  39  40  41",
+				" 42  43  44  45  46",
+				" 47  48  49  50* 51",
+				" 52  53  54  55  56",
+				" 57  58  59  60  61",
+				" 62  63  64  65  66",
+				" 67  68  69  70  71",
+				"[72] 73",
+			},
+		},
+		{
+			name:   "Season With St Tibs Day",
+			args:   []string{"chs", "3166"},
+			today:  "2000-02-29",
+			locale: "en",
+			want: []string{
+				"     Chaos 3166",
+				" 56  57  58  59",
+				"  [St. Tib's Day]",
+				"                 60",
+				" 61  62  63  64  65",
+			},
+		},
+		{
+			name:   "Season Without St Tibs Day",
+			args:   []string{"Chaos", "3165"},
+			today:  "2000-02-29",
+			locale: "en",
+			want: []string{
+				"     Chaos 3165",
+				" 56  57  58  59  60",
+				" 61  62  63  64  65",
+			},
+		},
+		{
+			name:   "Three Seasons Across YOLDs",
+			args:   []string{"-3", "Chaos", "3192"},
+			today:  "2026-01-05",
+			locale: "en",
+			want: []string{
+				" The Aftermath 3191        Chaos 3192           Discord 3192",
+				" SM  BT  PD  PP  SO    SM  BT  PD  PP  SO    SM  BT  PD  PP  SO",
+				"          1   2   3     1   2   3   4 [ 5]                1   2",
+				" 69  70  71  72  73    71  72  73            68  69  70  71  72",
+				strings.Repeat(" ", 45) + "73",
+			},
+		},
+		{
+			name:   "Whole YOLD",
+			args:   []string{"-y"},
+			today:  "2026-10-18",
+			locale: "de",
+			want: []string{
+				"                              3192",
+				"       Chaos               Zwietracht            Verwirrung",
+				" SM  BT  ST  PP  OU    SM  BT  ST  PP  OU    SM  BT  ST  PP  OU",
+				"     Bürokratie         Die Nachwirkung",
+				"[72] 73",
+			},
+		},
+		{
+			name:   "Lone YOLD",
+			args:   []string{"3193"},
+			today:  "2026-10-18",
+			locale: "en",
+			want: []string{
+				"                              3193",
+				"       Chaos                Discord              Confusion",
+			},
+		},
+		{
+			name:   "Unknown Season",
+			args:   []string{"Summer"},
+			today:  "today",
+			locale: "en",
+			want:   []string{"ddate: unknown season \"Summer\""},
+			exit:   1,
+		},
+		{
+			name:   "Invalid YOLD",
+			args:   []string{"Chaos", "MMXXVI"},
+			today:  "today",
+			locale: "en",
+			want:   []string{"ddate: invalid YOLD \"MMXXVI\""},
+			exit:   1,
+		},
+		{
+			name:   "Three Seasons Between Arguments",
+			args:   []string{"Chaos", "-3", "3192"},
+			today:  "2026-01-05",
+			locale: "en",
+			want: []string{
+				" The Aftermath 3191        Chaos 3192           Discord 3192",
+			},
+		},
+		{
+			name:   "Too Many Arguments",
+			args:   []string{"Chaos", "1", "3192"},
+			today:  "today",
+			locale: "en",
+			want:   []string{"ddate: too many arguments for calendar"},
+			exit:   1,
+		},
+	}
+
+	for _, test := range tests {
+		// shadow loop var to prevent nasty bugs
+		test := test
+
+		// trim whitespace from name of test case
+		name := strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+
+			return r
+		}, test.name)
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			var errBuf, outBuf bytes.Buffer
+			var exit int
+
+			defer os.MockAndLockStderr(&errBuf).Unlock()
+			defer os.MockAndLockStdout(&outBuf).Unlock()
+			defer os.MockAndLockEnv(nil).Unlock()
+			defer os.MockAndLockExit(func(code int) {
+				exit = code
+
+				// simulate exit
+				panic("exit")
+			}).Unlock()
+
+			// Act
+			func() {
+				// catch "fake" panic
+				defer func() {
+					if err := recover(); err != nil && err != "exit" {
+						panic(err)
+					}
+				}()
+
+				// the options come last, so that the arguments are parsed first
+				args := append(append([]string{}, test.args...), "--utc", "-d", test.today, "--locale", test.locale)
+				calendar("ddate", args)
+			}()
+
+			// Assert
+			if have, want := exit, test.exit; have != want {
+				t.Fatalf("exit code: have %d, want %d", have, want)
+			}
+
+			out := outBuf.String()
+			if test.exit != 0 {
+				out = errBuf.String()
+			}
+
+			// every expected line must appear, in order
+			lines := strings.Split(out, "\n")
+
+			for _, want := range test.want {
+				for len(lines) > 0 && lines[0] != want {
+					lines = lines[1:]
+				}
+
+				if len(lines) == 0 {
+					t.Fatalf("output: missing line %q in:\n%s", want, out)
+				}
+			}
+
+			if test.exit == 0 && strings.Contains(out, fmt.Sprintln(" ")) {
+				t.Errorf("output: trailing whitespace in:\n%s", out)
+			}
+		})
+	}
+}
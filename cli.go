@@ -0,0 +1,191 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/norwd/ddate/format"
+	"github.com/norwd/ddate/internal/os"
+)
+
+// version of ddate, set at build time with -ldflags "-X main.version=...", or
+// otherwise taken from the module version of the build.
+var version = ""
+
+// usage describes the arguments of a command for its help.
+type usage struct {
+	args    string // synopsis of the arguments, after the options
+	summary string // description of the command
+}
+
+// command is a mode of ddate other than converting a date, selected by the
+// first argument.
+type command struct {
+	run   func(self string, args []string)
+	usage usage
+}
+
+// commands are the modes of ddate other than converting a date, by name.
+var commands = map[string]command{
+	"cal":      {calendar, calendarUsage},
+	"convert":  {convert, convertUsage},
+	"csv":      {rewriteCSV, csvUsage},
+	"holydays": {holydays, holydaysUsage},
+	"ics":      {calendarFile, icsUsage},
+	"next":     {next, nextUsage},
+	"serve":    {serve, serveUsage},
+}
+
+// aliases are the names of the program which select a command, so that a
+// single binary can be installed with symlinks named after its commands.
+var aliases = map[string]string{
+	"dcal":      "cal",
+	"dconvert":  "convert",
+	"dholydays": "holydays",
+}
+
+// mainUsage describes the arguments of ddate when converting a date.
+var mainUsage = usage{
+	args:    "[+format] [<DD> <MM> <YYYY> | - ]",
+	summary: "Print the Discordian date of today, or of the given Gregorian date.",
+}
+
+// versionString returns the version of ddate.
+func versionString() string {
+	if version != "" {
+		return version
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+
+	return "(devel)"
+}
+
+// flagSets collects the flag sets of the commands by name, instead of parsing
+// the command line, when it is not nil, so that the options of the commands
+// can be listed without running them.
+var flagSets map[string]*flag.FlagSet
+
+// newFlagSet returns an empty flag set for the named command, which reports
+// errors to the caller instead of printing them, and prints the usage of the
+// command on --help.
+func newFlagSet(self, name string, u usage) *flag.FlagSet {
+	// a command invoked through its alias is named after the alias
+	if name != "" && aliases[strings.TrimSuffix(self, ".exe")] != name {
+		self += " " + name
+	}
+
+	flags := flag.NewFlagSet(self, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	flags.Usage = func() {
+		w := flags.Output()
+
+		fmt.Fprintf(w, "Usage: %s [options] %s\n\n%s\n", self, u.args, u.summary)
+		fmt.Fprintf(w, "\nOptions:\n")
+		flags.PrintDefaults()
+	}
+
+	if flagSets != nil {
+		flagSets[name] = flags
+	}
+
+	return flags
+}
+
+// parseFlags parses the options of a command, which may come before, after, or
+// between its other arguments, and returns the other arguments. Arguments after
+// "--" are never options, and neither are negative numbers such as the year in
+// "ddate 1 1 -50", unless they name an option such as the -3 of cal.
+//
+// If the arguments ask for --help, the usage is printed and ok is false. If the
+// options cannot be parsed, the program exits with an error. While the flag
+// sets are collected, nothing is parsed and ok is false.
+func parseFlags(self string, flags *flag.FlagSet, args []string) (rest []string, ok bool) {
+	if flagSets != nil {
+		return nil, false
+	}
+
+	for {
+		if len(args) > 0 {
+			if _, err := strconv.Atoi(args[0]); err == nil && flags.Lookup(strings.TrimLeft(args[0], "-")) == nil {
+				rest, args = append(rest, args[0]), args[1:]
+				continue
+			}
+		}
+
+		err := flags.Parse(args)
+		if errors.Is(err, flag.ErrHelp) {
+			flags.SetOutput(os.Stdout)
+			flags.Usage()
+
+			return nil, false
+		} else if err != nil {
+			errorf("%s: %s", self, err)
+			return nil, false
+		}
+
+		remaining := flags.Args()
+
+		if len(remaining) == 0 {
+			return rest, true
+		} else if n := len(args) - len(remaining); n > 0 && args[n-1] == "--" {
+			return append(rest, remaining...), true
+		}
+
+		rest, args = append(rest, remaining[0]), remaining[1:]
+	}
+}
+
+// printMainUsage prints the usage of ddate with the list of its commands.
+func printMainUsage(flags *flag.FlagSet) {
+	w := flags.Output()
+
+	fmt.Fprintf(w, "Usage:\n")
+	fmt.Fprintf(w, "  %s [options] %s\n", flags.Name(), mainUsage.args)
+	fmt.Fprintf(w, "  %s --reverse [options] <Season> <DD> <YOLD>\n", flags.Name())
+	fmt.Fprintf(w, "  %s <command> [options] [arguments]\n", flags.Name())
+	fmt.Fprintf(w, "\n%s\n", mainUsage.summary)
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	fmt.Fprintf(w, "\nCommands:\n")
+
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-10s%s\n", name, commands[name].usage.summary)
+	}
+
+	fmt.Fprintf(w, "\nOptions:\n")
+	flags.PrintDefaults()
+
+	fmt.Fprintf(w, "\nDirectives:\n")
+	writeDirectives(w)
+
+	fmt.Fprintf(w, "\nRun '%s <command> --help' for the options of a command.\n", flags.Name())
+}
+
+// writeDirectives writes a table of the format directives with their
+// descriptions.
+func writeDirectives(w io.Writer) error {
+	out := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	for _, info := range format.Directives() {
+		fmt.Fprintf(out, "  %s\t%s\n", info, info.Summary())
+	}
+
+	return out.Flush()
+}
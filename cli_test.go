@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/norwd/ddate/internal/os"
+)
+
+func TestHelp(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string   // name of the test case
+		self string   // name of the application
+		args []string // arguments to pass to main
+		want []string // expected lines of output, in order
+	}{
+		{
+			name: "Main",
+			self: "ddate",
+			args: []string{"--help"},
+			want: []string{
+				"Usage:",
+				"  ddate [options] [+format] [<DD> <MM> <YYYY> | - ]",
+				"Commands:",
+				"  cal       Print a calendar of the current season, or of the given season or YOLD.",
+				"  next      List the next holydays from today, by default only the next one.",
+				"  serve     Answer conversions of dates over HTTP.",
+				"Options:",
+				"  -reverse",
+				"Run 'ddate <command> --help' for the options of a command.",
+			},
+		},
+		{
+			name: "Command",
+			self: "ddate",
+			args: []string{"holydays", "-h"},
+			want: []string{
+				"Usage: ddate holydays [options] [<YOLD>]",
+				"List the holydays of the current YOLD, or of the given YOLD.",
+				"Options:",
+				"  -utc",
+			},
+		},
+		{
+			name: "Command After Arguments",
+			self: "ddate",
+			args: []string{"cal", "Chaos", "--help"},
+			want: []string{
+				"Usage: ddate cal [options] [-y | -3] [<Season>] [<YOLD>]",
+				"  -y\tprint every season of the YOLD",
+			},
+		},
+		{
+			name: "Alias",
+			self: "dconvert",
+			args: []string{"--help"},
+			want: []string{
+				"Usage: dconvert [options] <Season> <DD> <YOLD> | St. Tib's Day <YOLD>",
+			},
+		},
+		{
+			name: "Version",
+			self: "ddate",
+			args: []string{"--utc", "--version"},
+			want: []string{
+				"ddate " + versionString(),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		// shadow loop var to prevent nasty bugs
+		test := test
+
+		// trim whitespace from name of test case
+		name := strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+
+			return r
+		}, test.name)
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			var errBuf, outBuf bytes.Buffer
+			var exitCalls int
+
+			defer os.MockAndLockStderr(&errBuf).Unlock()
+			defer os.MockAndLockStdout(&outBuf).Unlock()
+			defer os.MockAndLockArgs(test.self, test.args).Unlock()
+			defer os.MockAndLockEnv(nil).Unlock()
+			defer os.MockAndLockExit(func(code int) {
+				exitCalls++
+
+				// simulate exit
+				panic("exit")
+			}).Unlock()
+
+			// Act
+			func() {
+				// catch "fake" panic
+				defer func() {
+					if err := recover(); err != nil && err != "exit" {
+						panic(err)
+					}
+				}()
+
+				main()
+			}()
+
+			// Assert
+			if exitCalls != 0 {
+				t.Fatalf("exit called %d times, want 0 (stderr: %q)", exitCalls, errBuf.String())
+			}
+
+			out := outBuf.String()
+
+			// every expected line must appear, in order
+			lines := strings.Split(out, "\n")
+
+			for _, want := range test.want {
+				for len(lines) > 0 && lines[0] != want {
+					lines = lines[1:]
+				}
+
+				if len(lines) == 0 {
+					t.Fatalf("output: missing line %q in:\n%s", want, out)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,445 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/norwd/ddate/format"
+	"github.com/norwd/ddate/internal/os"
+)
+
+// completionUsage describes the arguments of the completion command.
+var completionUsage = usage{
+	args:    "bash|zsh|fish",
+	summary: "Print the shell completion script for bash, zsh, or fish.",
+}
+
+// shells are the writers of the completion scripts, by the name of the shell.
+var shells = map[string]func(w io.Writer, c completions) error{
+	"bash": writeBashCompletion,
+	"fish": writeFishCompletion,
+	"zsh":  writeZshCompletion,
+}
+
+// zonesScript prints the names of the time zones of the system, one per line,
+// at the time of completion, since the names of the zones built into ddate
+// cannot be listed.
+const zonesScript = `awk '!/^#/ { print $3 }' "${TZDIR:-/usr/share/zoneinfo}/zone1970.tab" 2>/dev/null
+	echo UTC`
+
+func init() {
+	// completion is added here, as it lists the commands, including itself
+	commands["completion"] = command{completion, completionUsage}
+}
+
+// completion prints the completion script for the given shell.
+func completion(self string, args []string) {
+	flags := newFlagSet(self, "completion", completionUsage)
+
+	args, ok := parseFlags(self, flags, args)
+	if !ok {
+		return
+	}
+
+	if len(args) == 0 {
+		errorf("%s: missing shell, want bash, zsh, or fish", self)
+		return
+	} else if len(args) > 1 {
+		errorf("%s: too many arguments for completion", self)
+		return
+	}
+
+	write, ok := shells[args[0]]
+	if !ok {
+		errorf("%s: unknown shell %q, want bash, zsh, or fish", self, args[0])
+		return
+	}
+
+	if err := write(os.Stdout, newCompletions(self)); err != nil {
+		errorf("%s: %s", self, err)
+	}
+}
+
+// completions are the words completed by the completion scripts.
+type completions struct {
+	self       string                 // name of the program
+	commands   []string               // names of the commands, in order
+	summaries  map[string]string      // summaries of the commands, by name
+	aliases    []string               // names of the aliases, in order
+	options    map[string][]option    // options of the commands, "" for none
+	values     []option               // every option which takes a value
+	directives []format.DirectiveInfo // format directives, in order
+	shells     []string               // names of the shells, in order
+}
+
+// option is a command line option, with the values completed for it.
+type option struct {
+	name   string   // name of the option, without dashes
+	usage  string   // description of the option
+	value  bool     // whether the option takes a value
+	values []string // words completed for the value
+	files  bool     // whether file names are completed for the value
+	zones  bool     // whether time zone names are completed for the value
+}
+
+// flag returns the option as it is completed, with one dash for a single
+// letter and two otherwise.
+func (o option) flag() string {
+	if len(o.name) == 1 {
+		return "-" + o.name
+	}
+
+	return "--" + o.name
+}
+
+// pattern returns a shell case pattern which matches the option with either
+// one or two dashes.
+func (o option) pattern() string {
+	return "--" + o.name + "|-" + o.name
+}
+
+// newCompletions collects the commands and their options, without running
+// them.
+func newCompletions(self string) completions {
+	c := completions{
+		self:       self,
+		summaries:  map[string]string{},
+		options:    map[string][]option{},
+		directives: format.Directives(),
+	}
+
+	flagSets = map[string]*flag.FlagSet{}
+	defer func() { flagSets = nil }()
+
+	printDate(self, nil)
+
+	for name, command := range commands {
+		command.run(self, nil)
+
+		c.commands = append(c.commands, name)
+		c.summaries[name] = command.usage.summary
+	}
+
+	for name := range aliases {
+		c.aliases = append(c.aliases, name)
+	}
+
+	for name := range shells {
+		c.shells = append(c.shells, name)
+	}
+
+	sort.Strings(c.commands)
+	sort.Strings(c.aliases)
+	sort.Strings(c.shells)
+
+	seen := map[string]bool{}
+
+	for name, flags := range flagSets {
+		flags.VisitAll(func(f *flag.Flag) {
+			o := option{name: f.Name, value: true}
+			_, o.usage = flag.UnquoteUsage(f)
+
+			if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+				o.value = false
+			}
+
+			switch o.name {
+			case "directives", "fortune", "locale-file":
+				o.files = true
+			case "tz":
+				o.zones = true
+			case "locale":
+				o.values = format.Locales()
+			case "output":
+				o.values = []string{"text", "json"}
+			}
+
+			c.options[name] = append(c.options[name], o)
+
+			if o.value && !seen[o.name] {
+				c.values, seen[o.name] = append(c.values, o), true
+			}
+		})
+	}
+
+	sort.Slice(c.values, func(i, j int) bool {
+		return c.values[i].name < c.values[j].name
+	})
+
+	return c
+}
+
+// token returns the text completed for a directive, which is followed by an
+// opening parenthesis if it takes an argument.
+func token(info format.DirectiveInfo) string {
+	if info.Argument != "" {
+		return string(info.Directive) + "("
+	}
+
+	return string(info.Directive)
+}
+
+// shellQuote quotes the text as a single word for bash and zsh.
+func shellQuote(text string) string {
+	return "'" + strings.ReplaceAll(text, "'", `'\''`) + "'"
+}
+
+// fishQuote quotes the text as a single word for fish.
+func fishQuote(text string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(text) + "'"
+}
+
+// writeBashCompletion writes the completion script for bash.
+func writeBashCompletion(w io.Writer, c completions) error {
+	var b strings.Builder
+
+	fn := "_" + c.self
+
+	fmt.Fprintf(&b, "# bash completion for %s, generated by \"%s completion bash\".\n", c.self, c.self)
+	fmt.Fprintf(&b, "#\n# Load it in ~/.bashrc with: source <(%s completion bash)\n\n", c.self)
+
+	fmt.Fprintf(&b, "%s_zones() {\n\t%s\n}\n\n", fn, zonesScript)
+
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	fmt.Fprintf(&b, "\tlocal cur=${COMP_WORDS[COMP_CWORD]} prev=${COMP_WORDS[COMP_CWORD-1]}\n")
+	fmt.Fprintf(&b, "\tlocal cmd= options\n\n")
+
+	fmt.Fprintf(&b, "\tcase ${COMP_WORDS[0]##*/} in\n")
+
+	for _, name := range c.aliases {
+		fmt.Fprintf(&b, "\t%s) cmd=%s ;;\n", name, aliases[name])
+	}
+
+	fmt.Fprintf(&b, "\t*)\n\t\tif ((COMP_CWORD > 1)); then\n\t\t\tcase ${COMP_WORDS[1]} in\n")
+	fmt.Fprintf(&b, "\t\t\t%s) cmd=${COMP_WORDS[1]} ;;\n", strings.Join(c.commands, "|"))
+	fmt.Fprintf(&b, "\t\t\tesac\n\t\tfi\n\t\t;;\n\tesac\n\n")
+
+	fmt.Fprintf(&b, "\tcase $prev in\n")
+
+	for _, o := range c.values {
+		switch {
+		case o.files:
+			fmt.Fprintf(&b, "\t%s) COMPREPLY=($(compgen -f -- \"$cur\")) ;;\n", o.pattern())
+		case o.zones:
+			fmt.Fprintf(&b, "\t%s) COMPREPLY=($(compgen -W \"$(%s_zones)\" -- \"$cur\")) ;;\n", o.pattern(), fn)
+		case len(o.values) > 0:
+			fmt.Fprintf(&b, "\t%s) COMPREPLY=($(compgen -W %s -- \"$cur\")) ;;\n", o.pattern(), shellQuote(strings.Join(o.values, " ")))
+		default:
+			fmt.Fprintf(&b, "\t%s) ;;\n", o.pattern())
+		}
+	}
+
+	fmt.Fprintf(&b, "\t*) false ;;\n\tesac && return\n\n")
+
+	fmt.Fprintf(&b, "\tcase $cmd in\n")
+
+	for _, name := range append([]string{""}, c.commands...) {
+		var flags []string
+		for _, o := range c.options[name] {
+			flags = append(flags, o.flag())
+		}
+
+		fmt.Fprintf(&b, "\t%s) options=%s ;;\n", shellQuote(name), shellQuote(strings.Join(flags, " ")))
+	}
+
+	fmt.Fprintf(&b, "\tesac\n\n")
+
+	fmt.Fprintf(&b, "\tcase $cur in\n")
+	fmt.Fprintf(&b, "\t-*)\n\t\tCOMPREPLY=($(compgen -W \"$options\" -- \"$cur\"))\n\t\t;;\n")
+	fmt.Fprintf(&b, "\t+*)\n\t\tlocal base=${cur%%\\%%*} directive\n\n")
+	fmt.Fprintf(&b, "\t\tfor directive in")
+
+	for _, info := range c.directives {
+		fmt.Fprintf(&b, " %s", shellQuote(token(info)))
+	}
+
+	fmt.Fprintf(&b, "; do\n")
+	fmt.Fprintf(&b, "\t\t\t[[ $base$directive == \"$cur\"* ]] && COMPREPLY+=(\"$base$directive\")\n")
+	fmt.Fprintf(&b, "\t\tdone\n\n\t\tcompopt -o nospace\n\t\t;;\n")
+	fmt.Fprintf(&b, "\t*)\n\t\tif [[ -z $cmd ]] && ((COMP_CWORD == 1)); then\n")
+	fmt.Fprintf(&b, "\t\t\tCOMPREPLY=($(compgen -W %s -- \"$cur\"))\n", shellQuote(strings.Join(c.commands, " ")))
+	fmt.Fprintf(&b, "\t\telif [[ $cmd == completion ]]; then\n")
+	fmt.Fprintf(&b, "\t\t\tCOMPREPLY=($(compgen -W %s -- \"$cur\"))\n", shellQuote(strings.Join(c.shells, " ")))
+	fmt.Fprintf(&b, "\t\tfi\n\t\t;;\n\tesac\n}\n\n")
+
+	fmt.Fprintf(&b, "complete -F %s %s\n", fn, strings.Join(append([]string{c.self}, c.aliases...), " "))
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// writeZshCompletion writes the completion script for zsh, which may either be
+// saved in a directory of $fpath, or sourced.
+func writeZshCompletion(w io.Writer, c completions) error {
+	var b strings.Builder
+
+	fn := "_" + c.self
+	names := strings.Join(append([]string{c.self}, c.aliases...), " ")
+
+	fmt.Fprintf(&b, "#compdef %s\n\n", names)
+	fmt.Fprintf(&b, "# zsh completion for %s, generated by \"%s completion zsh\".\n", c.self, c.self)
+	fmt.Fprintf(&b, "#\n# Save it as %s in a directory of $fpath, or load it in ~/.zshrc with:\n", fn)
+	fmt.Fprintf(&b, "# source <(%s completion zsh)\n\n", c.self)
+
+	fmt.Fprintf(&b, "%s_zones() {\n\t%s\n}\n\n", fn, zonesScript)
+
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	fmt.Fprintf(&b, "\tlocal cmd prev=${words[CURRENT-1]}\n")
+	fmt.Fprintf(&b, "\tlocal -a options commands directives\n\n")
+
+	fmt.Fprintf(&b, "\tcase ${words[1]:t} in\n")
+
+	for _, name := range c.aliases {
+		fmt.Fprintf(&b, "\t%s) cmd=%s ;;\n", name, aliases[name])
+	}
+
+	fmt.Fprintf(&b, "\t*)\n\t\tif ((CURRENT > 2)); then\n\t\t\tcase ${words[2]} in\n")
+	fmt.Fprintf(&b, "\t\t\t%s) cmd=${words[2]} ;;\n", strings.Join(c.commands, "|"))
+	fmt.Fprintf(&b, "\t\t\tesac\n\t\tfi\n\t\t;;\n\tesac\n\n")
+
+	fmt.Fprintf(&b, "\tcase $prev in\n")
+
+	for _, o := range c.values {
+		switch {
+		case o.files:
+			fmt.Fprintf(&b, "\t%s) _files; return ;;\n", o.pattern())
+		case o.zones:
+			fmt.Fprintf(&b, "\t%s) compadd -- ${(f)\"$(%s_zones)\"}; return ;;\n", o.pattern(), fn)
+		case len(o.values) > 0:
+			fmt.Fprintf(&b, "\t%s) compadd -- %s; return ;;\n", o.pattern(), strings.Join(o.values, " "))
+		default:
+			fmt.Fprintf(&b, "\t%s) return ;;\n", o.pattern())
+		}
+	}
+
+	fmt.Fprintf(&b, "\tesac\n\n")
+
+	fmt.Fprintf(&b, "\tcase $cmd in\n")
+
+	for _, name := range append([]string{""}, c.commands...) {
+		fmt.Fprintf(&b, "\t%s)\n\t\toptions=(\n", shellQuote(name))
+
+		for _, o := range c.options[name] {
+			fmt.Fprintf(&b, "\t\t\t%s\n", shellQuote(o.flag()+":"+o.usage))
+		}
+
+		fmt.Fprintf(&b, "\t\t)\n\t\t;;\n")
+	}
+
+	fmt.Fprintf(&b, "\tesac\n\n")
+
+	fmt.Fprintf(&b, "\tcase $PREFIX in\n")
+	fmt.Fprintf(&b, "\t-*)\n\t\t_describe -t options option options\n\t\t;;\n")
+	fmt.Fprintf(&b, "\t+*)\n\t\tdirectives=(\n")
+
+	for _, info := range c.directives {
+		fmt.Fprintf(&b, "\t\t\t%s\n", shellQuote(strings.TrimPrefix(token(info), "%")+":"+info.Summary()))
+	}
+
+	fmt.Fprintf(&b, "\t\t)\n\n")
+	fmt.Fprintf(&b, "\t\tif compset -P '*%%'; then\n")
+	fmt.Fprintf(&b, "\t\t\t_describe -t directives directive directives -S ''\n")
+	fmt.Fprintf(&b, "\t\telse\n\t\t\tcompset -P '*'\n")
+	fmt.Fprintf(&b, "\t\t\t_describe -t directives directive directives -P %% -S ''\n")
+	fmt.Fprintf(&b, "\t\tfi\n\t\t;;\n")
+	fmt.Fprintf(&b, "\t*)\n\t\tif [[ -z $cmd ]] && ((CURRENT == 2)); then\n\t\t\tcommands=(\n")
+
+	for _, name := range c.commands {
+		fmt.Fprintf(&b, "\t\t\t\t%s\n", shellQuote(name+":"+c.summaries[name]))
+	}
+
+	fmt.Fprintf(&b, "\t\t\t)\n\n\t\t\t_describe -t commands command commands\n")
+	fmt.Fprintf(&b, "\t\telif [[ $cmd == completion ]]; then\n")
+	fmt.Fprintf(&b, "\t\t\tcompadd -- %s\n", strings.Join(c.shells, " "))
+	fmt.Fprintf(&b, "\t\tfi\n\t\t;;\n\tesac\n}\n\n")
+
+	fmt.Fprintf(&b, "if [[ $funcstack[1] == %s ]]; then\n\t%s \"$@\"\nelse\n\tcompdef %s %s\nfi\n", fn, fn, fn, names)
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// writeFishCompletion writes the completion script for fish.
+func writeFishCompletion(w io.Writer, c completions) error {
+	var b strings.Builder
+
+	fn := "__" + c.self
+	commands := strings.Join(c.commands, " ")
+
+	fmt.Fprintf(&b, "# fish completion for %s, generated by \"%s completion fish\".\n", c.self, c.self)
+	fmt.Fprintf(&b, "#\n# Save it as ~/.config/fish/completions/%s.fish, or load it with:\n", c.self)
+	fmt.Fprintf(&b, "# %s completion fish | source\n\n", c.self)
+
+	fmt.Fprintf(&b, "function %s_zones\n", fn)
+	fmt.Fprintf(&b, "\tset -l dir /usr/share/zoneinfo\n\tset -q TZDIR; and set dir $TZDIR\n\n")
+	fmt.Fprintf(&b, "\tawk '!/^#/ { print $3 }' $dir/zone1970.tab 2>/dev/null\n\techo UTC\nend\n\n")
+
+	fmt.Fprintf(&b, "function %s_directives\n", fn)
+	fmt.Fprintf(&b, "\tset -l token (commandline -ct)\n")
+	fmt.Fprintf(&b, "\tstring match -q -- '+*' $token; or return\n\n")
+	fmt.Fprintf(&b, "\tset -l base (string replace -r -- '%%[^%%]*$' '' $token)\n\n")
+	fmt.Fprintf(&b, "\tprintf '%%s%%s\\t%%s\\n' \\\n")
+
+	for i, info := range c.directives {
+		end := " \\"
+		if i == len(c.directives)-1 {
+			end = ""
+		}
+
+		fmt.Fprintf(&b, "\t\t$base %s %s%s\n", fishQuote(token(info)), fishQuote(info.Summary()), end)
+	}
+
+	fmt.Fprintf(&b, "end\n\n")
+
+	fmt.Fprintf(&b, "complete -c %s -f\n", c.self)
+
+	for _, name := range c.commands {
+		fmt.Fprintf(&b, "complete -c %s -n __fish_use_subcommand -a %s -d %s\n", c.self, name, fishQuote(c.summaries[name]))
+	}
+
+	for _, name := range append([]string{""}, c.commands...) {
+		condition := fmt.Sprintf("'__fish_seen_subcommand_from %s'", name)
+		if name == "" {
+			condition = fmt.Sprintf("'not __fish_seen_subcommand_from %s'", commands)
+		}
+
+		for _, o := range c.options[name] {
+			fmt.Fprintf(&b, "complete -c %s -n %s", c.self, condition)
+
+			if len(o.name) == 1 {
+				fmt.Fprintf(&b, " -s %s", o.name)
+			} else {
+				fmt.Fprintf(&b, " -l %s", o.name)
+			}
+
+			switch {
+			case o.files:
+				fmt.Fprintf(&b, " -r -F")
+			case o.zones:
+				fmt.Fprintf(&b, " -x -a '(%s_zones)'", fn)
+			case len(o.values) > 0:
+				fmt.Fprintf(&b, " -x -a %s", fishQuote(strings.Join(o.values, " ")))
+			case o.value:
+				fmt.Fprintf(&b, " -x")
+			}
+
+			fmt.Fprintf(&b, " -d %s\n", fishQuote(o.usage))
+		}
+	}
+
+	fmt.Fprintf(&b, "complete -c %s -n '__fish_seen_subcommand_from completion' -a %s\n", c.self, fishQuote(strings.Join(c.shells, " ")))
+	fmt.Fprintf(&b, "complete -c %s -a '(%s_directives)'\n", c.self, fn)
+
+	for _, name := range c.aliases {
+		fmt.Fprintf(&b, "complete -c %s -w %s\n", name, fishQuote(c.self+" "+aliases[name]))
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
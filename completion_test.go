@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/norwd/ddate/internal/os"
+)
+
+func TestCompletion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string   // name of the test case
+		args []string // arguments to pass to main
+		want []string // expected lines of output, in order
+		exit int      // expected error code
+	}{
+		{
+			name: "Bash",
+			args: []string{"completion", "bash"},
+			want: []string{
+				"_ddate() {",
+				"\tdcal) cmd=cal ;;",
+				"\t--locale|-locale) COMPREPLY=($(compgen -W 'de en es fr' -- \"$cur\")) ;;",
+				"\t--tz|-tz) COMPREPLY=($(compgen -W \"$(_ddate_zones)\" -- \"$cur\")) ;;",
+				"\t'cal') options='-3 -d --date --locale --locale-file --tz --utc -y' ;;",
+				"\t\t\t[[ $base$directive == \"$cur\"* ]] && COMPREPLY+=(\"$base$directive\")",
+				"\t\t\tCOMPREPLY=($(compgen -W 'cal completion convert csv holydays ics next serve' -- \"$cur\"))",
+				"complete -F _ddate ddate dcal dconvert dholydays",
+			},
+		},
+		{
+			name: "Zsh",
+			args: []string{"completion", "zsh"},
+			want: []string{
+				"#compdef ddate dcal dconvert dholydays",
+				"\t--output|-output) compadd -- text json; return ;;",
+				"\t\t\t'--strict:fail on directives undefined on St. Tib'\\''s Day'",
+				"\t\t\t'B:formats the full name of the season (i.e. Bureaucracy)'",
+				"\t\t\t'b:formats the abbreviated name of the season (i.e. Bcy)'",
+				"\t\t\t\t'serve:Answer conversions of dates over HTTP.'",
+				"\tcompdef _ddate ddate dcal dconvert dholydays",
+			},
+		},
+		{
+			name: "Fish",
+			args: []string{"completion", "fish"},
+			want: []string{
+				"function __ddate_directives",
+				"\t\t$base '%a' 'formats the abbreviated name of the day of the week (i.e. PP)' \\",
+				"\t\t$base '%G(' 'formats the Gregorian date and time of day of the same instant, according to the strftime(3) layout in the parentheses (i.e. 1995-09-26 21:05)' \\",
+				"complete -c ddate -n __fish_use_subcommand -a ics -d 'Print an iCalendar file of the holydays of a range of YOLDs.'",
+				"complete -c ddate -n '__fish_seen_subcommand_from cal' -l tz -x -a '(__ddate_zones)' -d 'use the time zone Area/City, defaults to $TZ'",
+				"complete -c ddate -n '__fish_seen_subcommand_from csv' -l tsv -d 'read and write tab separated values'",
+				"complete -c dcal -w 'ddate cal'",
+			},
+		},
+		{
+			name: "Unknown Shell",
+			args: []string{"completion", "tcsh"},
+			want: []string{"ddate: unknown shell \"tcsh\", want bash, zsh, or fish"},
+			exit: 1,
+		},
+		{
+			name: "Missing Shell",
+			args: []string{"completion"},
+			want: []string{"ddate: missing shell, want bash, zsh, or fish"},
+			exit: 1,
+		},
+	}
+
+	for _, test := range tests {
+		// shadow loop var to prevent nasty bugs
+		test := test
+
+		// trim whitespace from name of test case
+		name := strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+
+			return r
+		}, test.name)
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			var errBuf, outBuf bytes.Buffer
+			var exit int
+
+			defer os.MockAndLockStderr(&errBuf).Unlock()
+			defer os.MockAndLockStdout(&outBuf).Unlock()
+			defer os.MockAndLockArgs("ddate", test.args).Unlock()
+			defer os.MockAndLockEnv(nil).Unlock()
+			defer os.MockAndLockExit(func(code int) {
+				exit = code
+
+				// simulate exit
+				panic("exit")
+			}).Unlock()
+
+			// Act
+			func() {
+				// catch "fake" panic
+				defer func() {
+					if err := recover(); err != nil && err != "exit" {
+						panic(err)
+					}
+				}()
+
+				main()
+			}()
+
+			// Assert
+			if have, want := exit, test.exit; have != want {
+				t.Fatalf("exit code: have %d, want %d (stderr: %q)", have, want, errBuf.String())
+			}
+
+			if flagSets != nil {
+				t.Errorf("flag sets: still collected after completion")
+			}
+
+			out := outBuf.String()
+			if test.exit != 0 {
+				out = errBuf.String()
+			}
+
+			// every expected line must appear, in order
+			lines := strings.Split(out, "\n")
+
+			for _, want := range test.want {
+				for len(lines) > 0 && lines[0] != want {
+					lines = lines[1:]
+				}
+
+				if len(lines) == 0 {
+					t.Fatalf("output: missing line %q in:\n%s", want, out)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	"github.com/norwd/ddate/discordian"
+)
+
+// convertUsage describes the arguments of the convert command.
+var convertUsage = usage{
+	args:    "<Season> <DD> <YOLD> | St. Tib's Day <YOLD>",
+	summary: "Print the Gregorian date of a Discordian date.",
+}
+
+// convert prints the Gregorian date of the Discordian date given by the
+// arguments, it is the same as --reverse.
+func convert(self string, args []string) {
+	var s settings
+
+	flags := newFlagSet(self, "convert", convertUsage)
+	s.register(flags)
+
+	args, ok := parseFlags(self, flags, args)
+	if !ok {
+		return
+	}
+
+	if err := s.resolve(); err != nil {
+		errorf("%s: %s", self, err)
+		return
+	}
+
+	toGregorian(self, args, s.location, false)
+}
+
+// toGregorian prints the Gregorian date of the Discordian date given by the
+// arguments, as the season, day, and YOLD, or as St. Tib's Day and YOLD. The
+// date and errors are printed in the given output.
+func toGregorian(self string, args []string, location *time.Location, output outputFlag) {
+	if len(args) == 0 {
+		output.errorf("%s: not enough arguments for Discordian date", self)
+		return
+	}
+
+	date, err := discordian.Reverse(strings.Join(args, " "), location)
+	if err != nil {
+		output.errorf("%s: %s", self, err)
+	} else if output {
+		println(marshal(gregorianObject{Gregorian: date.Format(discordian.GregorianFormat)}))
+	} else {
+		println(date.Format(discordian.GregorianFormat))
+	}
+}
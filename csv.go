@@ -0,0 +1,265 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/norwd/ddate/discordian"
+	"github.com/norwd/ddate/format"
+	"github.com/norwd/ddate/internal/os"
+)
+
+// csvUsage describes the arguments of the csv command.
+var csvUsage = usage{
+	args:    "[+format]...",
+	summary: "Convert a column of Gregorian dates in CSV read from stdin.",
+}
+
+// rewriteCSV reads CSV, or TSV, from stdin and writes it to stdout with the
+// Gregorian dates of one column converted to Discordian dates, either in place
+// or in new columns appended to every record.
+//
+// Records are converted one at a time, so that input of any size is streamed.
+func rewriteCSV(self string, args []string) {
+	var s settings
+	var f formatting
+
+	flags := newFlagSet(self, "csv", csvUsage)
+	s.register(flags)
+	f.register(flags)
+
+	column := flags.String("column", "1", "`name or index` of the date column, counting from 1")
+	tsv := flags.Bool("tsv", false, "read and write tab separated values")
+	appendColumns := flags.Bool("append", false, "append the Discordian dates instead of replacing the column")
+	noHeader := flags.Bool("no-header", false, "do not treat the first record as a header")
+
+	args, ok := parseFlags(self, flags, args)
+	if !ok {
+		return
+	}
+
+	if err := s.resolve(); err != nil {
+		errorf("%s: %s", self, err)
+		return
+	}
+
+	opts, err := f.options(s.locale)
+	if err != nil {
+		errorf("%s: %s", self, err)
+		return
+	}
+
+	// Every argument is a format, which gives a column of its own
+	var layouts []string
+
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "+") {
+			errorf("%s: unexpected argument %q, want +format", self, arg)
+			return
+		}
+
+		layouts = append(layouts, strings.TrimPrefix(arg, "+"))
+	}
+
+	if len(layouts) == 0 {
+		layouts = []string{discordian.DefaultFormat}
+	} else if len(layouts) > 1 && !*appendColumns {
+		errorf("%s: cannot replace a column with more than one format", self)
+		return
+	}
+
+	// Select the column by index, or by name once the header is read
+	index := -1
+
+	if n, err := strconv.Atoi(*column); err == nil && n > 0 {
+		index = n - 1
+	} else if err == nil {
+		errorf("%s: invalid column %d", self, n)
+		return
+	} else if *noHeader {
+		errorf("%s: cannot select column %q without a header", self, *column)
+		return
+	}
+
+	stdin := bufio.NewReader(os.Stdin)
+
+	in := csv.NewReader(stdin)
+	in.FieldsPerRecord = -1
+	crlf := endsInCRLF(stdin)
+
+	var out recordWriter
+
+	// quotes are common in TSV, where they do not need to be escaped
+	if *tsv {
+		in.Comma, in.LazyQuotes = '\t', true
+		out = &tsvWriter{w: bufio.NewWriter(os.Stdout), useCRLF: crlf}
+	} else {
+		w := csv.NewWriter(os.Stdout)
+		w.UseCRLF = crlf
+		out = w
+	}
+
+	// fail writes the records converted so far before reporting the error
+	fail := func(format string, args ...interface{}) {
+		out.Flush()
+		errorf(format, args...)
+	}
+
+	for header := !*noHeader; ; header = false {
+		record, err := in.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			fail("%s: %s", self, err)
+			return
+		}
+
+		var cells []string
+
+		if header {
+			if index < 0 {
+				if index = indexOf(record, *column); index < 0 {
+					fail("%s: no column %q in header", self, *column)
+					return
+				}
+			}
+
+			cells = layouts
+		} else {
+			if index >= len(record) {
+				line, _ := in.FieldPos(0)
+				fail("%s: line %d: no column %d", self, line, index+1)
+				return
+			}
+
+			if cells, err = convertCell(record[index], s.today, layouts, opts); err != nil {
+				line, _ := in.FieldPos(index)
+				fail("%s: line %d: %s", self, line, err)
+				return
+			}
+		}
+
+		if *appendColumns {
+			record = append(record, cells...)
+		} else if !header {
+			record[index] = cells[0]
+		}
+
+		if err := out.Write(record); err != nil {
+			fail("%s: %s", self, err)
+			return
+		}
+	}
+
+	out.Flush()
+
+	if err := out.Error(); err != nil {
+		errorf("%s: %s", self, err)
+	}
+}
+
+// recordWriter writes records one at a time, as csv.Writer does.
+type recordWriter interface {
+	Write(record []string) error
+	Flush()
+	Error() error
+}
+
+// tsvWriter writes records as tab separated values. Unlike csv.Writer, it only
+// quotes the fields which could not be read back otherwise, so that quotes in
+// the other fields are written as they are.
+type tsvWriter struct {
+	w       *bufio.Writer // buffered stdout
+	useCRLF bool          // end the lines in CRLF instead of LF
+}
+
+// Write writes a record, quoting the fields with tabs or line breaks in them,
+// or which start with a quote.
+func (t *tsvWriter) Write(record []string) error {
+	for i, field := range record {
+		if i > 0 {
+			t.w.WriteByte('\t')
+		}
+
+		if strings.ContainsAny(field, "\t\r\n") || strings.HasPrefix(field, `"`) {
+			field = `"` + strings.ReplaceAll(field, `"`, `""`) + `"`
+
+			if t.useCRLF {
+				field = strings.ReplaceAll(field, "\n", "\r\n")
+			}
+		}
+
+		t.w.WriteString(field)
+	}
+
+	if t.useCRLF {
+		t.w.WriteByte('\r')
+	}
+
+	return t.w.WriteByte('\n')
+}
+
+// Flush writes the buffered records to stdout.
+func (t *tsvWriter) Flush() {
+	t.w.Flush()
+}
+
+// Error returns the error of any previous Write or Flush.
+func (t *tsvWriter) Error() error {
+	_, err := t.w.Write(nil)
+	return err
+}
+
+// convertCell returns the date in the cell in each of the layouts, or empty
+// cells if the cell is blank.
+func convertCell(cell string, now time.Time, layouts []string, opts []format.Option) ([]string, error) {
+	cells := make([]string, len(layouts))
+
+	if strings.TrimSpace(cell) == "" {
+		return cells, nil
+	}
+
+	date, err := discordian.Parse(cell, now)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, layout := range layouts {
+		if cells[i], err = backend(layout, date, opts...); err != nil {
+			return nil, err
+		}
+	}
+
+	return cells, nil
+}
+
+// indexOf returns the index of the first field with the given name, ignoring
+// surrounding whitespace, or -1 if there is none.
+func indexOf(fields []string, name string) int {
+	for i, field := range fields {
+		if strings.TrimSpace(field) == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// endsInCRLF returns whether the first line of the buffered input ends in CRLF,
+// without consuming any input.
+func endsInCRLF(r *bufio.Reader) bool {
+	for n := 64; ; n *= 2 {
+		b, err := r.Peek(n)
+		if i := bytes.IndexByte(b, '\n'); i >= 0 {
+			return i > 0 && b[i-1] == '\r'
+		} else if err != nil {
+			return false
+		}
+	}
+}
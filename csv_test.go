@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/norwd/ddate/format"
+	"github.com/norwd/ddate/internal/os"
+)
+
+func TestRewriteCSV(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string   // name of the test case
+		args   []string // arguments to pass to the filter
+		stdin  string   // records to read from stdin
+		stdout string   // expected output
+		stderr string   // expected error output
+		exit   int      // expected error code
+	}{
+		{
+			name:   "Replace First Column",
+			args:   []string{},
+			stdin:  "date,amount\n1995-09-26,\"1,000\"\n",
+			stdout: "date,amount\n\"Prickle-Prickle, Bureaucracy 50, 3161 YOLD\",\"1,000\"\n",
+		},
+		{
+			name:   "Append Columns By Name",
+			args:   []string{"--column", "date", "--append", "+%Y", "+%{%B %d%}"},
+			stdin:  "id,date,note\n1,26 9 1995,\"multi\nline\"\n2,,\n3,2000-02-29,\"say \"\"hi\"\"\"\n",
+			stdout: "id,date,note,%Y,%{%B %d%}\n1,26 9 1995,\"multi\nline\",3161,Bureaucracy 50\n2,,,,\n3,2000-02-29,\"say \"\"hi\"\"\",3166,St. Tib's Day\n",
+		},
+		{
+			name:   "TSV By Index Without Header",
+			args:   []string{"--tsv", "--no-header", "--column", "2", "+%e %B"},
+			stdin:  "a\t2000-01-01\n\"b\tc\"\t2000-01-02\n",
+			stdout: "a\t1st Chaos\n\"b\tc\"\t2nd Chaos\n",
+		},
+		{
+			name:   "CRLF With Quoted Line Breaks",
+			args:   []string{"--column", "date", "+%Y"},
+			stdin:  "date,note\r\n2000-01-01,\"multi\r\nline\"\r\n",
+			stdout: "date,note\r\n3166,\"multi\r\nline\"\r\n",
+		},
+		{
+			name:   "TSV With Quotes",
+			args:   []string{"--tsv", "--column", "2", "+%Y"},
+			stdin:  "item\tdate\r\n6\" pipe\t2000-01-01\r\n\"b\tc\"\t2000-01-02\r\n",
+			stdout: "item\tdate\r\n6\" pipe\t3166\r\n\"b\tc\"\t3166\r\n",
+		},
+		{
+			name:   "Bad Date",
+			args:   []string{"--column", "2"},
+			stdin:  "id,date\n1,2000-01-01\n2,bogus\n3,2000-01-03\n",
+			stdout: "id,date\n1,\"Sweetmorn, Chaos 1, 3166 YOLD\"\n",
+			stderr: "ddate: line 3: invalid date \"bogus\"\n",
+			exit:   1,
+		},
+		{
+			name:   "Missing Column",
+			args:   []string{"--column", "when"},
+			stdin:  "id,date\n",
+			stderr: "ddate: no column \"when\" in header\n",
+			exit:   1,
+		},
+		{
+			name:   "Short Record",
+			args:   []string{"--no-header", "--column", "2", "+%d"},
+			stdin:  "a,2000-01-01\nb\n",
+			stdout: "a,1\n",
+			stderr: "ddate: line 2: no column 2\n",
+			exit:   1,
+		},
+		{
+			name:   "Column Name Without Header",
+			args:   []string{"--no-header", "--column", "date"},
+			stderr: "ddate: cannot select column \"date\" without a header\n",
+			exit:   1,
+		},
+		{
+			name:   "Replace With Many Formats",
+			args:   []string{"+%Y", "+%B"},
+			stderr: "ddate: cannot replace a column with more than one format\n",
+			exit:   1,
+		},
+	}
+
+	for _, test := range tests {
+		// shadow loop var to prevent nasty bugs
+		test := test
+
+		// trim whitespace from name of test case
+		name := strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+
+			return r
+		}, test.name)
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			var errBuf, outBuf bytes.Buffer
+			var exit int
+
+			defer os.MockAndLockStdin(strings.NewReader(test.stdin)).Unlock()
+			defer os.MockAndLockStderr(&errBuf).Unlock()
+			defer os.MockAndLockStdout(&outBuf).Unlock()
+			defer os.MockAndLockEnv(nil).Unlock()
+			defer mockAndLockBackend(format.Format).Unlock()
+			defer os.MockAndLockExit(func(code int) {
+				exit = code
+
+				// simulate exit
+				panic("exit")
+			}).Unlock()
+
+			// Act
+			func() {
+				// catch "fake" panic
+				defer func() {
+					if err := recover(); err != nil && err != "exit" {
+						panic(err)
+					}
+				}()
+
+				rewriteCSV("ddate", append([]string{"--utc"}, test.args...))
+			}()
+
+			// Assert
+			if have, want := outBuf.String(), test.stdout; have != want {
+				t.Errorf("stdout: have %q, want %q", have, want)
+			}
+
+			if have, want := errBuf.String(), test.stderr; have != want {
+				t.Errorf("stderr: have %q, want %q", have, want)
+			}
+
+			if have, want := exit, test.exit; have != want {
+				t.Errorf("exit code: have %d, want %d", have, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/norwd/ddate/format"
+	"github.com/norwd/ddate/internal/os"
+)
+
+// directivesEnv is the environment variable which may name the directives file.
+const directivesEnv = "DDATE_DIRECTIVES"
+
+// directiveDefinition is a user-defined directive in a directives file, which
+// formats either a name for every season, a name for every weekday, or a fixed
+// text.
+type directiveDefinition struct {
+	Directive   format.Directive `json:"directive"`   // code of the directive, i.e. %P
+	Description string           `json:"description"` // what it formats, for --list-directives
+	Seasons     []string         `json:"seasons"`     // text for every season, from Chaos
+	Weekdays    []string         `json:"weekdays"`    // text for every weekday, from Sweetmorn
+	TibsDay     string           `json:"tibs_day"`    // text of the seasons or weekdays on St. Tib's Day
+	Text        string           `json:"text"`        // fixed text
+}
+
+// function returns the formatting function of the directive.
+func (def directiveDefinition) function() (func(format.Date) string, error) {
+	switch {
+	case def.Seasons != nil && len(def.Seasons) != 5:
+		return nil, fmt.Errorf("directive %s: have %d seasons, want 5", def.Directive, len(def.Seasons))
+	case def.Weekdays != nil && len(def.Weekdays) != format.DaysPerWeek:
+		return nil, fmt.Errorf("directive %s: have %d weekdays, want %d", def.Directive, len(def.Weekdays), format.DaysPerWeek)
+	case def.Seasons != nil && def.Weekdays == nil && def.Text == "":
+		return func(d format.Date) string {
+			if d.TibsDay && def.TibsDay != "" {
+				return def.TibsDay
+			}
+
+			return def.Seasons[d.Season]
+		}, nil
+	case def.Weekdays != nil && def.Seasons == nil && def.Text == "":
+		return func(d format.Date) string {
+			if d.TibsDay {
+				return def.TibsDay
+			}
+
+			return def.Weekdays[d.Weekday]
+		}, nil
+	case def.Text != "" && def.Seasons == nil && def.Weekdays == nil:
+		return func(format.Date) string {
+			return def.Text
+		}, nil
+	}
+
+	return nil, fmt.Errorf("directive %s: want exactly one of seasons, weekdays, or text", def.Directive)
+}
+
+// readDirectives registers the user-defined directives of the given file, which
+// holds a JSON array of directive definitions.
+func readDirectives(name string) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	var defs []directiveDefinition
+
+	decoder := json.NewDecoder(file)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&defs); err != nil {
+		return fmt.Errorf("%s: invalid directives: %w", name, err)
+	}
+
+	for _, def := range defs {
+		fn, err := def.function()
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		if def.Description == "" {
+			return fmt.Errorf("%s: directive %s: missing description", name, def.Directive)
+		}
+
+		info := format.DirectiveInfo{Directive: def.Directive, Description: def.Description}
+
+		if err := format.RegisterDirective(info, fn); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+	"unicode"
+
+	"github.com/norwd/ddate/format"
+	"github.com/norwd/ddate/internal/os"
+)
+
+// TestReadDirectives is not parallel, so that the directives it registers are
+// removed again before the parallel tests list the directives.
+func TestReadDirectives(t *testing.T) {
+
+	tests := []struct {
+		name   string    // name of the test case
+		file   string    // contents of the directives file
+		layout string    // format string using the directives
+		date   time.Time // date to format
+		want   string    // expected output, or error
+	}{
+		{
+			name:   "Seasons",
+			file:   `[{"directive": "%P", "description": "formats the patron apostle", "seasons": ["Hung Mung", "Dr. Van Van Mojo", "Sri Syadasti", "Zarathud", "The Elder Malaclypse"]}]`,
+			layout: "%B: %P",
+			date:   time.Date(1995, time.September, 26, 0, 0, 0, 0, time.UTC),
+			want:   "Bureaucracy: Zarathud",
+		},
+		{
+			name:   "Weekdays On St Tibs Day",
+			file:   `[{"directive": "%W", "description": "formats the team on call", "weekdays": ["Ops", "Web", "Data", "Ops", "Web"], "tibs_day": "nobody"}]`,
+			layout: "%W is on call",
+			date:   time.Date(1996, time.February, 29, 0, 0, 0, 0, time.UTC),
+			want:   "nobody is on call",
+		},
+		{
+			name:   "Text",
+			file:   `[{"directive": "%T", "description": "formats the name of the team", "text": "Legion of Dynamic Discord"}]`,
+			layout: "Hail %T!",
+			date:   time.Date(1995, time.September, 26, 0, 0, 0, 0, time.UTC),
+			want:   "Hail Legion of Dynamic Discord!",
+		},
+		{
+			name: "Wrong Number Of Seasons",
+			file: `[{"directive": "%U", "description": "formats nothing", "seasons": ["Chaos"]}]`,
+			want: "directives.json: directive %U: have 1 seasons, want 5",
+		},
+		{
+			name: "Both Seasons And Text",
+			file: `[{"directive": "%U", "description": "formats nothing", "seasons": ["a", "b", "c", "d", "e"], "text": "f"}]`,
+			want: "directives.json: directive %U: want exactly one of seasons, weekdays, or text",
+		},
+		{
+			name: "Missing Description",
+			file: `[{"directive": "%U", "text": "f"}]`,
+			want: "directives.json: directive %U: missing description",
+		},
+		{
+			name: "Built In",
+			file: `[{"directive": "%A", "description": "formats nothing", "text": "f"}]`,
+			want: "directives.json: directive %A is built in",
+		},
+		{
+			name: "Unknown Field",
+			file: `[{"directive": "%U", "format": "%A"}]`,
+			want: `directives.json: invalid directives: json: unknown field "format"`,
+		},
+	}
+
+	for _, test := range tests {
+		// shadow loop var to prevent nasty bugs
+		test := test
+
+		// trim whitespace from name of test case
+		name := strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+
+			return r
+		}, test.name)
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			defer os.MockAndLockFS(fstest.MapFS{"directives.json": {Data: []byte(test.file)}}).Unlock()
+
+			// Act
+			err := readDirectives("directives.json")
+			t.Cleanup(func() { unregisterDirectives(test.file) })
+
+			// Assert
+			if test.layout == "" {
+				if err == nil || err.Error() != test.want {
+					t.Fatalf("error: have %v, want %q", err, test.want)
+				}
+
+				return
+			} else if err != nil {
+				t.Fatalf("error: have %q, want nil", err)
+			}
+
+			if have, err := format.Format(test.layout, test.date); err != nil {
+				t.Fatalf("error: have %q, want nil", err)
+			} else if have != test.want {
+				t.Errorf("output: have %q, want %q", have, test.want)
+			}
+		})
+	}
+}
+
+// unregisterDirectives removes the user-defined directives of a directives
+// file, so that they do not leak into the other tests.
+func unregisterDirectives(file string) {
+	var defs []directiveDefinition
+
+	// an invalid file registers nothing
+	_ = json.Unmarshal([]byte(file), &defs)
+
+	for _, def := range defs {
+		format.UnregisterDirective(def.Directive)
+	}
+}
@@ -0,0 +1,41 @@
+// Package discordian converts dates between the Gregorian and the Discordian
+// calendars, and parses Gregorian and Discordian dates.
+//
+// The Discordian dates themselves, and their formatting, are provided by the
+// format package, which this package builds on.
+package discordian
+
+import (
+	"time"
+
+	"github.com/norwd/ddate/format"
+)
+
+// DefaultFormat is the format of Discordian dates used by ddate if no other
+// format is given, e.g. "Sweetmorn, Chaos 1, 3192 YOLD".
+const DefaultFormat = "%{%A, %B %d%}, %Y YOLD"
+
+// GregorianFormat is the layout of Gregorian dates converted from Discordian
+// dates, e.g. "2026-01-01".
+const GregorianFormat = "2006-01-02"
+
+// Convert returns the Discordian date of the Gregorian date of t.
+func Convert(t time.Time) format.Date {
+	return format.NewDate(t)
+}
+
+// Format returns the Discordian date of t in the default format.
+func Format(t time.Time, opts ...format.Option) (string, error) {
+	return format.Format(DefaultFormat, t, opts...)
+}
+
+// Reverse parses a Discordian date, as accepted by format.ParseDate, and
+// returns its Gregorian date at midnight in the given time zone.
+func Reverse(s string, loc *time.Location) (time.Time, error) {
+	date, err := format.ParseDate(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return date.Time(loc)
+}
@@ -0,0 +1,218 @@
+package discordian
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateLayouts are the absolute date formats accepted by ParseGregorian, in
+// order.
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// dateUnits are the units of relative dates, as the years, months, days, and
+// duration they add.
+var dateUnits = map[string]struct {
+	years, months, days int
+	duration            time.Duration
+}{
+	"year":      {years: 1},
+	"month":     {months: 1},
+	"fortnight": {days: 14},
+	"week":      {days: 7},
+	"day":       {days: 1},
+	"hour":      {duration: time.Hour},
+	"minute":    {duration: time.Minute},
+	"min":       {duration: time.Minute},
+	"second":    {duration: time.Second},
+	"sec":       {duration: time.Second},
+}
+
+// dateWeekdays are the Gregorian weekdays by name and abbreviation.
+var dateWeekdays = map[string]time.Weekday{}
+
+func init() {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		dateWeekdays[strings.ToLower(day.String())] = day
+		dateWeekdays[strings.ToLower(day.String()[:3])] = day
+	}
+}
+
+// ParseGregorian parses a Gregorian date the way date(1) does with its --date
+// option.
+//
+// The date may be given as an ISO 8601 date (2006-01-02), with or without a
+// time of day, as an RFC 3339 timestamp, or as the number of seconds since the
+// Unix epoch prefixed with an at sign (@1136214245). Dates without a time zone
+// are taken in the given location and all other dates are converted to it.
+//
+// Otherwise, the date is a sequence of relative items applied to now, such as
+// "today", "tomorrow", "yesterday", "+5 days", "2 weeks ago", "next monday",
+// or "last year".
+func ParseGregorian(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	loc := now.Location()
+
+	if strings.HasPrefix(s, "@") {
+		seconds, err := strconv.ParseInt(s[1:], 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date %q", s)
+		}
+
+		return time.Unix(seconds, 0).In(loc), nil
+	}
+
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t.In(loc), nil
+		}
+	}
+
+	t, err := parseRelativeDate(strings.Fields(strings.ToLower(s)), now)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", s)
+	}
+
+	return t, nil
+}
+
+// parseRelativeDate applies the relative date items in the fields to now.
+func parseRelativeDate(fields []string, now time.Time) (time.Time, error) {
+	if len(fields) == 0 {
+		return time.Time{}, fmt.Errorf("empty date")
+	}
+
+	t := now
+
+	for len(fields) > 0 {
+		field := fields[0]
+		fields = fields[1:]
+
+		switch field {
+		case "now", "today":
+			continue
+		case "tomorrow":
+			t = t.AddDate(0, 0, 1)
+			continue
+		case "yesterday":
+			t = t.AddDate(0, 0, -1)
+			continue
+		}
+
+		// The amount is either a signed number, or next, last, and this.
+		var amount int
+
+		switch field {
+		case "next":
+			amount = 1
+		case "last":
+			amount = -1
+		case "this":
+			amount = 0
+		default:
+			n, err := strconv.Atoi(field)
+			if err != nil {
+				// A bare weekday is the next one, or today.
+				if day, ok := dateWeekdays[field]; ok {
+					t = t.AddDate(0, 0, (int(day-t.Weekday())+7)%7)
+					continue
+				}
+
+				return time.Time{}, fmt.Errorf("unknown date item %q", field)
+			}
+
+			amount = n
+		}
+
+		if len(fields) == 0 {
+			return time.Time{}, fmt.Errorf("missing unit after %q", field)
+		}
+
+		unit := fields[0]
+		fields = fields[1:]
+
+		if len(fields) > 0 && fields[0] == "ago" {
+			amount = -amount
+			fields = fields[1:]
+		}
+
+		if day, ok := dateWeekdays[unit]; ok {
+			t = addWeekdays(t, day, amount)
+			continue
+		}
+
+		change, ok := dateUnits[strings.TrimSuffix(unit, "s")]
+		if !ok {
+			return time.Time{}, fmt.Errorf("unknown date unit %q", unit)
+		}
+
+		t = t.AddDate(amount*change.years, amount*change.months, amount*change.days)
+		t = t.Add(time.Duration(amount) * change.duration)
+	}
+
+	return t, nil
+}
+
+// addWeekdays moves t to the n-th given weekday after it, or before it if n is
+// negative. If n is zero, t is moved to the given weekday of its own week.
+func addWeekdays(t time.Time, day time.Weekday, n int) time.Time {
+	switch {
+	case n > 0:
+		ahead := (int(day-t.Weekday())+6)%7 + 1
+		return t.AddDate(0, 0, ahead+7*(n-1))
+	case n < 0:
+		behind := (int(t.Weekday()-day)+6)%7 + 1
+		return t.AddDate(0, 0, -behind+7*(n+1))
+	default:
+		return t.AddDate(0, 0, int(day-t.Weekday()))
+	}
+}
+
+// ParseDDMMYYYY parses strings representing a day, month, and year as a time,
+// at midnight in the given time zone.
+//
+// Note that the time returned will normalise the day, month, and year values if
+// they are outside their allowed range. E.g. Oct 32 becomes Nov 1.
+func ParseDDMMYYYY(dayStr, monthStr, yearStr string, loc *time.Location) (t time.Time, err error) {
+	var day, month, year, hour, min, sec, nsec int
+
+	if day, err = strconv.Atoi(dayStr); err != nil {
+		return
+	}
+
+	if month, err = strconv.Atoi(monthStr); err != nil {
+		return
+	}
+
+	if year, err = strconv.Atoi(yearStr); err != nil {
+		return
+	}
+
+	t = time.Date(year, time.Month(month), day, hour, min, sec, nsec, loc)
+
+	return
+}
+
+// Parse parses a Gregorian date given either as DD MM YYYY, in the same way as
+// ParseDDMMYYYY, or in any of the forms accepted by ParseGregorian, relative to
+// now.
+func Parse(s string, now time.Time) (time.Time, error) {
+	if fields := strings.Fields(s); len(fields) == 0 {
+		return time.Time{}, errors.New("empty date")
+	} else if len(fields) == 3 {
+		if date, err := ParseDDMMYYYY(fields[0], fields[1], fields[2], now.Location()); err == nil {
+			return date, nil
+		}
+	}
+
+	return ParseGregorian(s, now)
+}
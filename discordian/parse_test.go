@@ -0,0 +1,380 @@
+package discordian
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+)
+
+func TestParseGregorian(t *testing.T) {
+	t.Parallel()
+
+	// Sunday, October 18th, 2026 at 12:34:56 in New York
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("time zone error: have %q, want nil", err)
+	}
+
+	now := time.Date(2026, time.October, 18, 12, 34, 56, 0, newYork)
+
+	tests := []struct {
+		name string    // name of the test case
+		have string    // input date
+		want time.Time // expected time (if zero expect err)
+	}{
+		{
+			name: "ISO Date",
+			have: "2027-06-18",
+			want: time.Date(2027, time.June, 18, 0, 0, 0, 0, newYork),
+		},
+		{
+			name: "ISO Date And Time",
+			have: "2027-06-18 09:15",
+			want: time.Date(2027, time.June, 18, 9, 15, 0, 0, newYork),
+		},
+		{
+			name: "RFC 3339 Timestamp",
+			have: "2027-06-18T02:00:00Z",
+			want: time.Date(2027, time.June, 17, 22, 0, 0, 0, newYork),
+		},
+		{
+			name: "Unix Seconds",
+			have: "@0",
+			want: time.Date(1969, time.December, 31, 19, 0, 0, 0, newYork),
+		},
+		{
+			name: "Now",
+			have: "now",
+			want: now,
+		},
+		{
+			name: "Today",
+			have: " Today ",
+			want: now,
+		},
+		{
+			name: "Tomorrow",
+			have: "tomorrow",
+			want: now.AddDate(0, 0, 1),
+		},
+		{
+			name: "Yesterday",
+			have: "yesterday",
+			want: now.AddDate(0, 0, -1),
+		},
+		{
+			name: "Plus Days",
+			have: "+5 days",
+			want: now.AddDate(0, 0, 5),
+		},
+		{
+			name: "Minus Weeks",
+			have: "-2 weeks",
+			want: now.AddDate(0, 0, -14),
+		},
+		{
+			name: "Months Ago",
+			have: "3 months ago",
+			want: now.AddDate(0, -3, 0),
+		},
+		{
+			name: "Next Year",
+			have: "next year",
+			want: now.AddDate(1, 0, 0),
+		},
+		{
+			name: "Hours",
+			have: "1 hour",
+			want: now.Add(time.Hour),
+		},
+		{
+			name: "Next Weekday",
+			have: "next monday",
+			want: time.Date(2026, time.October, 19, 12, 34, 56, 0, newYork),
+		},
+		{
+			name: "Next Same Weekday",
+			have: "next sunday",
+			want: time.Date(2026, time.October, 25, 12, 34, 56, 0, newYork),
+		},
+		{
+			name: "Last Weekday",
+			have: "last fri",
+			want: time.Date(2026, time.October, 16, 12, 34, 56, 0, newYork),
+		},
+		{
+			name: "Last Same Weekday",
+			have: "last sunday",
+			want: time.Date(2026, time.October, 11, 12, 34, 56, 0, newYork),
+		},
+		{
+			name: "Bare Weekday",
+			have: "wednesday",
+			want: time.Date(2026, time.October, 21, 12, 34, 56, 0, newYork),
+		},
+		{
+			name: "Bare Same Weekday",
+			have: "sunday",
+			want: now,
+		},
+		{
+			name: "Combined Items",
+			have: "tomorrow +1 week",
+			want: now.AddDate(0, 0, 8),
+		},
+		{
+			name: "Empty",
+			have: "",
+		},
+		{
+			name: "Unknown Word",
+			have: "someday",
+		},
+		{
+			name: "Missing Unit",
+			have: "+5",
+		},
+		{
+			name: "Unknown Unit",
+			have: "5 eons",
+		},
+		{
+			name: "Invalid Unix Seconds",
+			have: "@soon",
+		},
+	}
+
+	for _, test := range tests {
+		// shadow loop var to prevent nasty bugs
+		test := test
+
+		// trim whitespace from name of test case
+		name := strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+
+			return r
+		}, test.name)
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act
+			date, err := ParseGregorian(test.have, now)
+
+			// Assert
+			if test.want.IsZero() {
+				if err == nil {
+					t.Fatalf("error: have nil, want error")
+				}
+
+				return // don't keep testing, expected failure detected
+			}
+
+			if err != nil {
+				t.Fatalf("error: have %q, want nil", err)
+			}
+
+			if have, want := date, test.want; !have.Equal(want) {
+				t.Errorf("date: have %s, want %s", have, want)
+			}
+
+			if have, want := date.Location(), newYork; have != want {
+				t.Errorf("location: have %s, want %s", have, want)
+			}
+		})
+	}
+}
+
+func TestParseDDMMYYYY(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string    // name of the test case
+		have [3]string // input in DD, MM, and YYYY
+		want [3]int    // expected day, month, and year
+	}{
+		{
+			name: "All Empty",
+			have: [3]string{"", "", ""},
+		},
+		{
+			name: "All Zeros",
+			have: [3]string{"0", "0", "0"},
+			want: [3]int{30, 11, -1},
+		},
+		{
+			name: "Valid Date",
+			have: [3]string{"6", "8", "1999"},
+			want: [3]int{6, 8, 1999},
+		},
+		{
+			name: "Valid Date Leading Zeros",
+			have: [3]string{"06", "08", "01999"},
+			want: [3]int{6, 8, 1999},
+		},
+		{
+			name: "Invalid Day",
+			have: [3]string{"_6", "8", "1999"},
+			want: [3]int{0, 0, 0},
+		},
+		{
+			name: "Invalid Month",
+			have: [3]string{"6", "_8", "1999"},
+			want: [3]int{0, 0, 0},
+		},
+		{
+			name: "Invalid Year",
+			have: [3]string{"6", "8", "_1999"},
+			want: [3]int{0, 0, 0},
+		},
+		{
+			name: "Invalid Date",
+			have: [3]string{"32", "10", "2022"},
+			want: [3]int{1, 11, 2022},
+		},
+	}
+
+	for _, test := range tests {
+		// shadow loop var to prevent nasty bugs
+		test := test
+
+		// trim whitespace from name of test case
+		name := strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+
+			return r
+		}, test.name)
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			day, month, year := test.have[0], test.have[1], test.have[2]
+
+			// Act
+			date, err := ParseDDMMYYYY(day, month, year, time.Local)
+
+			// Assert
+			if _, want := strconv.Atoi(day); want != nil {
+				if want := want.Error(); err == nil {
+					t.Fatalf("error: have nil, want %q", want)
+				} else if have := err.Error(); have != want {
+					t.Fatalf("error: have %q, want %q", have, want)
+				}
+
+				return // don't keep testing, expected failure detected
+			} else if have, want := date.Day(), test.want[0]; have != want && err == nil {
+				t.Fatalf("day: have %d, want %d", have, want)
+			}
+
+			if _, want := strconv.Atoi(month); want != nil {
+				if want := want.Error(); err == nil {
+					t.Fatalf("error: have nil, want %q", want)
+				} else if have := err.Error(); have != want {
+					t.Fatalf("error: have %q, want %q", have, want)
+				}
+
+				return // don't keep testing, expected failure detected
+			} else if have, want := int(date.Month()), test.want[1]; have != want && err == nil {
+				t.Fatalf("want: have %d, want %d", have, want)
+			}
+
+			if _, want := strconv.Atoi(year); want != nil {
+				if want := want.Error(); err == nil {
+					t.Fatalf("error: have nil, want %q", want)
+				} else if have := err.Error(); have != want {
+					t.Fatalf("error: have %q, want %q", have, want)
+				}
+
+				return // don't keep testing, expected failure detected
+			} else if have, want := date.Year(), test.want[2]; have != want && err == nil {
+				t.Fatalf("year: have %d, want %d", have, want)
+			}
+
+			if err != nil {
+				t.Fatalf("error: have %q, want nil", err)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	// Sunday, October 18th, 2026 at 12:34:56 in UTC
+	now := time.Date(2026, time.October, 18, 12, 34, 56, 0, time.UTC)
+
+	tests := []struct {
+		name string    // name of the test case
+		have string    // input date
+		want time.Time // expected time (if zero expect err)
+	}{
+		{
+			name: "DD MM YYYY",
+			have: " 26 9  1995 ",
+			want: time.Date(1995, time.September, 26, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "ISO Date And Time",
+			have: "1995-09-26 09:15",
+			want: time.Date(1995, time.September, 26, 9, 15, 0, 0, time.UTC),
+		},
+		{
+			name: "Three Relative Items",
+			have: "2 days ago",
+			want: now.AddDate(0, 0, -2),
+		},
+		{
+			name: "Empty",
+			have: " \t",
+		},
+		{
+			name: "Garbage",
+			have: "26 IX 1995",
+		},
+	}
+
+	for _, test := range tests {
+		// shadow loop var to prevent nasty bugs
+		test := test
+
+		// trim whitespace from name of test case
+		name := strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+
+			return r
+		}, test.name)
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act
+			have, err := Parse(test.have, now)
+
+			// Assert
+			if test.want.IsZero() {
+				if err == nil {
+					t.Fatalf("error: have nil, want error for %q", test.have)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("error: have %q, want nil", err)
+			}
+
+			if !have.Equal(test.want) {
+				t.Errorf("date: have %s, want %s", have, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package discordian
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/norwd/ddate/format"
+)
+
+// FuncMap returns the functions for converting and formatting dates in a
+// text/template or html/template, formatted with the given options:
+//
+//	ddate      the date in the layout, or the default format, e.g.
+//	           {{ ddate .CreatedAt "%A, %B %d" }} or {{ ddate .CreatedAt }}
+//	holyday    the name of the holyday of the date, or nothing, as %H
+//	yold       the YOLD of the date, as a number
+//	discordian the Discordian date, e.g. {{ (discordian .Date).Season }}
+//
+// The result can be passed to the Funcs method of either template package.
+func FuncMap(opts ...format.Option) map[string]interface{} {
+	return map[string]interface{}{
+		"ddate": func(t time.Time, layout ...string) (string, error) {
+			switch len(layout) {
+			case 0:
+				return format.Format(DefaultFormat, t, opts...)
+			case 1:
+				return format.Format(layout[0], t, opts...)
+			default:
+				return "", fmt.Errorf("too many layouts for ddate: %d", len(layout))
+			}
+		},
+		"holyday": func(t time.Time) (string, error) {
+			return format.Format("%H", t, opts...)
+		},
+		"yold": func(t time.Time) int {
+			return Convert(t).YOLD
+		},
+		"discordian": Convert,
+	}
+}
@@ -0,0 +1,106 @@
+package discordian
+
+import (
+	htmltemplate "html/template"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+	"unicode"
+
+	"github.com/norwd/ddate/format"
+)
+
+func TestFuncMap(t *testing.T) {
+	t.Parallel()
+
+	data := struct{ Date time.Time }{
+		Date: time.Date(1995, time.September, 26, 12, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name string          // name of the test case
+		text string          // template to execute
+		opts []format.Option // formatting options
+		want string          // expected output (if empty expect err)
+	}{
+		{
+			name: "Default Format",
+			text: `{{ ddate .Date }}`,
+			want: "Prickle-Prickle, Bureaucracy 50, 3161 YOLD",
+		},
+		{
+			name: "Layout",
+			text: `{{ ddate .Date "%A, the %e of %B" }}`,
+			want: "Prickle-Prickle, the 50th of Bureaucracy",
+		},
+		{
+			name: "Holyday And YOLD",
+			text: `{{ holyday .Date }} {{ yold .Date }}`,
+			want: "Bureflux 3161",
+		},
+		{
+			name: "Locale",
+			text: `{{ holyday .Date }} in {{ (discordian .Date).Season }}`,
+			opts: []format.Option{format.WithLocale(format.German)},
+			want: "Büroflux in Bureaucracy",
+		},
+		{
+			name: "Bad Layout",
+			text: `{{ ddate .Date "%Q" }}`,
+		},
+		{
+			name: "Too Many Layouts",
+			text: `{{ ddate .Date "%A" "%B" }}`,
+		},
+	}
+
+	for _, test := range tests {
+		// shadow loop var to prevent nasty bugs
+		test := test
+
+		// trim whitespace from name of test case
+		name := strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+
+			return r
+		}, test.name)
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			text := template.Must(template.New(name).Funcs(FuncMap(test.opts...)).Parse(test.text))
+			html := htmltemplate.Must(htmltemplate.New(name).Funcs(FuncMap(test.opts...)).Parse(test.text))
+
+			var textOut, htmlOut strings.Builder
+
+			// Act
+			textErr := text.Execute(&textOut, data)
+			htmlErr := html.Execute(&htmlOut, data)
+
+			// Assert
+			if test.want == "" {
+				if textErr == nil || htmlErr == nil {
+					t.Fatalf("error: have %v and %v, want errors", textErr, htmlErr)
+				}
+
+				return
+			}
+
+			if textErr != nil || htmlErr != nil {
+				t.Fatalf("error: have %v and %v, want nil", textErr, htmlErr)
+			}
+
+			if have, want := textOut.String(), test.want; have != want {
+				t.Errorf("text/template: have %q, want %q", have, want)
+			}
+
+			if have, want := htmlOut.String(), test.want; have != want {
+				t.Errorf("html/template: have %q, want %q", have, want)
+			}
+		})
+	}
+}
@@ -11,36 +11,210 @@
 //
 // Usage:
 //
-//     ddate [+format] [<DD> <MM> <YYYY>]
+//     ddate [--strict] [--xday <YYYY-MM-DD>] [--fortune <file>]... [--daily-quote]
+//           [--locale <name> | --locale-file <file>] [--utc | --tz <Area/City>]
+//           [--directives <file>] [--output text|json]
+//           [+format] [<DD> <MM> <YYYY> | -d <date>]
+//     ddate [options...] [+format] [--keep-going] (--stdin | -)
+//     ddate --reverse [--utc | --tz ...] [--output ...] <Season> <DD> <YOLD>
+//     ddate --reverse [--utc | --tz ...] [--output ...] St. Tib's Day <YOLD>
+//     ddate cal [--locale ...] [--utc | --tz ...] [-d <date>] [-y | -3] [<Season>] [<YOLD>]
+//     ddate holydays [--locale ...] [--utc | --tz ...] [-d <date>] [<YOLD>]
+//     ddate next [--locale ...] [--utc | --tz ...] [-d <date>] [<count>]
+//     ddate convert [--utc | --tz ...] <Season> <DD> <YOLD>
+//     ddate ics [--locale ...] [-d <date>] [--seasons] [<YOLD> [<YOLD>]]
+//     ddate serve [options...] [--listen <address>]
+//     ddate csv [options...] [--column <name|index>] [--tsv] [--no-header]
+//           [--append] [+format]...
+//     ddate [<command>] --help
+//     ddate completion bash|zsh|fish
+//     ddate --version
+//     ddate --list-directives [--output text|json]
 //
 // Options:
 //
 // There are a number of formatting directives available to format the date.
 //
-//     - %A formats the full name of the day of the week (i.e. Sweetmorn).
-//     - %a formats the abbreviated name of the day of the week (i.e. SM).
-//     - %B formats the full name of the season (i.e. Chaos).
-//     - %b formats the abbreviated name of the day of the week (i.e. Chs).
-//     - %d formats the ordinal number of the day in the season (i.e. 23).
-//     - %e formats the cardinal number of the day in the season (i.e. 23rd).
-//     - %Y formats the ordinal year of our lady of of discord (i.e. 3161).
-//     - %y formats the cardinal year of our lady of discord (i.e. 3161st).
-//     - %H formats the name of the current Holyday, if any (i.e. Confuflux).
+//     - %A formats the full name of the day of the week (i.e. Prickle-Prickle).
+//     - %a formats the abbreviated name of the day of the week (i.e. PP).
+//     - %B formats the full name of the season (i.e. Bureaucracy).
+//     - %b formats the abbreviated name of the season (i.e. Bcy).
+//     - %d formats the number of the day in the season (i.e. 50).
+//     - %e formats the ordinal number of the day in the season (i.e. 50th).
+//     - %Y formats the year of our lady of discord (i.e. 3161).
+//     - %y formats the ordinal year of our lady of discord (i.e. 3161st).
+//     - %H formats the name of the current Holyday, if any (i.e. Bureflux).
 //     - %N is a magic code to prevent the remainder of the format string being
 //       printed unless the date is a Holyday.
 //     - %n formats a newline character.
 //     - %t formats a tab character.
-//     - %% formats a literal percent sign character.
-//     - %X formats the cardinal number of days remaining until X-Day from the
-//       given date.
-//     - %{ and %} are used to enclose the part of the string which is to be
-//       replaced with the words "St. Tib's Day" if the current day is St. Tib's Day.
+//     - %% formats a literal percent sign character (i.e. %).
+//     - %X formats the number of days remaining until X-Day from the given date
+//       (i.e. 2434624).
+//     - %{ begins the part of the string which is replaced with the words "St.
+//       Tib's Day" if the current day is St. Tib's Day.
+//     - %} ends the part of the string begun by %{.
+//     - %G(...) formats the Gregorian date and time of day of the same instant,
+//       according to the strftime(3) layout in the parentheses (i.e. 1995-09-26
+//       21:05).
 //     - %. Try it and see...
 //
+// The layout of %G(...) may use the strftime(3) conversions %a %A %b %B %C %d
+// %D %e %F %h %H %I %j %k %l %m %M %n %p %R %s %S %t %T %u %w %y %Y %z %Z and
+// %%, and %) formats a closing parenthesis. The directives are also listed by
+// --list-directives, as a table, or as a JSON array with --output json.
+//
+// As with date(1), the percent sign of every directive may be followed by
+// flags and a width. The flag - does not pad, _ pads with spaces, 0 pads with
+// zeros, ^ converts to upper case, and # converts to the opposite case, which
+// is upper case unless the text already is (i.e. %#B formats CHAOS and %#a
+// formats sm). A width pads the text on the left to at least that many
+// characters, with zeros for %d, %Y, and %X, and with spaces otherwise (i.e.
+// %03d formats 005, %-d formats 5, %^A formats SWEETMORN, and %12B formats
+// "       Chaos"), so columns of dates line up. A directive which formats
+// nothing, such as %d on St. Tib's Day, is not padded.
+//
+// An unknown directive, a lone percent sign at the end of the format, or a %{
+// without a matching %} (or vice versa) is an error, which is reported together
+// with its byte offset in the format.
+//
 // The date is optional and ddate will default to the current date if omitted,
 // however, if specified the date must be given in a space separated DD MM YYYY
 // format.
 //
+// Alternatively, the date may be given with -d or --date, in the same way as
+// for date(1). This accepts an ISO 8601 date with or without a time of day
+// (i.e. 2026-10-18 or 2026-10-18 09:30), an RFC 3339 timestamp, the number of
+// seconds since the Unix epoch prefixed with an at sign (i.e. @1792281600), or
+// relative dates such as today, tomorrow, yesterday, +5 days, 2 weeks ago, next
+// monday, or last year.
+//
+// The %X directive counts down to X-Day, which is July 5th, 8661 unless another
+// date is given with --xday or the DDATE_XDAY environment variable, both in
+// YYYY-MM-DD format. Once the date is past X-Day, the count is negative and
+// gives the number of days since X-Day.
+//
+// Directives of your own can be defined in a JSON file given with --directives
+// or named by the DDATE_DIRECTIVES environment variable, which holds an array
+// of objects with the fields directive (a percent sign and a single character
+// which is not a built-in directive), description, and either seasons (the text
+// for each of the five seasons, from Chaos), weekdays (the text for each of the
+// five weekdays, from Sweetmorn), or text (a fixed text). With seasons or
+// weekdays, tibs_day is the text on St. Tib's Day. The directives are then
+// listed by --list-directives as well. Programs embedding ddate can instead
+// register their own directives with format.Register.
+//
+//     [{"directive": "%P", "description": "formats the patron apostle",
+//       "seasons": ["Hung Mung", "Dr. Van Van Mojo", "Sri Syadasti",
+//                   "Zarathud", "The Elder Malaclypse"]}]
+//
+// The %. directive formats a quote, selected at random from the built-in quotes
+// of the Principia Discordia. Quotes can instead be read from one or more files
+// given with --fortune, in the format of fortune(6), where quotes are separated
+// by lines holding a single percent sign. With --daily-quote, the same quote is
+// selected for everyone on the same date.
+//
+// The current date, and the date given as DD MM YYYY, are taken in the local
+// time zone, or in the time zone named by the TZ environment variable if it is
+// set. With --utc, Coordinated Universal Time is used instead, and with --tz
+// the time zone of the given Area/City (i.e. Europe/Berlin). The time zone
+// database is built into ddate, so time zones are available even if the system
+// has none.
+//
+// The names of the weekdays, seasons, and holydays, and the ordinal suffixes
+// are formatted in the language of the locale given with --locale, or named by
+// the LC_ALL, LC_TIME, or LANG environment variables, in that order. English
+// (en), German (de), French (fr), and Spanish (es) are built in, and English is
+// used if the locale in the environment is not one of them. Other languages can
+// be loaded with --locale-file from a JSON file with the fields of
+// format.Locale.
+//
+// With --stdin, or when the only argument is a dash, dates are read from the
+// standard input, one per line, either as DD MM YYYY or in any of the forms
+// accepted by --date, and each is printed in the format on a line of its own.
+// A line which is not a date is an error, which is reported with its line
+// number. With --keep-going, the error is reported and the remaining lines are
+// converted, and ddate exits with a non-zero status once all lines are done.
+//
+// With --output json, the date is printed as a JSON object instead, with the
+// fields gregorian (in YYYY-MM-DD format), yold, season, day, weekday, holyday,
+// tibs_day, x_day (the days until X-Day), and formatted (the date in the
+// format). The season, day, and weekday are left out on St. Tib's Day, and the
+// holyday on days which are not holydays. With --stdin, one object is printed
+// per line. Errors are then printed as JSON objects with an error field.
+//
+// With --reverse, the arguments are instead read as a Discordian date, given as
+// the full or abbreviated name of the season, the day of the season, and the
+// YOLD, or as St. Tib's Day and the YOLD. The corresponding Gregorian date is
+// printed in YYYY-MM-DD format, or with --output json as a JSON object with the
+// field gregorian. St. Tib's Day only exists in YOLDs which fall in a Gregorian
+// leap year.
+//
+// With cal, ddate prints a calendar of the current season as a grid of five-day
+// weeks, in the same way as cal(1). Holydays are marked with an asterisk, today
+// is enclosed in brackets, and St. Tib's Day is printed on a line of its own
+// between Chaos 59 and Chaos 60. Another season can be given by name, with or
+// without a YOLD. With -y, or when only a YOLD is given, every season of the
+// YOLD is printed, and with -3 the previous and next season are printed around
+// the season. The date used as today may be changed with --date.
+//
+// With holydays, ddate lists the holydays of the current YOLD, or of the given
+// YOLD, with their Gregorian dates. St. Tib's Day is listed in the YOLDs which
+// have one. With convert, the arguments are a Discordian date which is
+// converted to the Gregorian calendar, in the same way as with --reverse.
+//
+// With ics, ddate prints an iCalendar file, as in RFC 5545, with an all-day
+// event for every apostle day, season day, and St. Tib's Day of the current
+// YOLD, or of the range of YOLDs from the first to the last YOLD given, which
+// spans at most 100 YOLDs from 1167 to 11165, the Gregorian years 1 to 9999.
+// With --seasons, an event is added on the first day of every season. Every
+// event has a UID made of its YOLD and English name (i.e. 3192-mungday@ddate),
+// so that importing the file again updates the events instead of duplicating
+// them.
+//
+// With serve, ddate answers conversions over HTTP, on 127.0.0.1:8023 unless
+// another address is given with --listen. GET /today answers today's date,
+// /date/<date> the date in any form accepted by --date, /reverse/<date> the
+// Gregorian date of a Discordian date, /holydays and /holydays/<YOLD> the
+// holydays of the current or given YOLD, and /holydays.ics an iCalendar feed of
+// the holydays of the YOLDs given by the from and to query parameters, at most
+// 100 YOLDs as with ics, with the first days of the seasons if seasons=true.
+// The format, tz, and locale query parameters replace the format, --tz, and
+// --locale, and with output=json the answer is JSON, as with --output json. Bad
+// requests are answered with status 400 and the error.
+//
+// With csv, ddate reads CSV from the standard input and writes it to the
+// standard output with the dates in one column converted to Discordian dates.
+// The column is selected with --column by its name in the header, or by its
+// index counting from 1, and is the first column by default. With --no-header,
+// the first record is not a header and the column must be given by its index.
+// The dates may be given in any of the forms accepted by --date, or as DD MM
+// YYYY, and empty cells are left empty. The column is replaced by the date in
+// the format, or with --append, a column is appended to every record for every
+// format given, and named after the format in the header. Records are converted
+// as they are read, and fields are quoted as needed, so any CSV can be piped
+// through. With --tsv, tab separated values are read and written instead, where
+// only the fields with tabs or line breaks, or starting with a quote, are
+// quoted. The lines end in CRLF if the first line of the input does.
+//
+// With next, ddate lists the next holyday from today, including today, or the
+// given number of next holydays, at most 1100, in the same way as holydays.
+//
+// With completion, ddate prints a completion script for bash, zsh, or fish,
+// which completes the commands, their options, the format directives with
+// their descriptions, the names of the built-in locales, and the names of the
+// time zones of the system (i.e. source <(ddate completion bash) in ~/.bashrc,
+// or ddate completion fish | source).
+//
+// Every command prints its usage and options with --help, and ddate prints its
+// version with --version. Options may be given before, after, or between the
+// other arguments, and every argument after -- is not an option. A single word
+// which is not a command is an error, rather than a date.
+//
+// A single ddate binary provides all of its commands. When it is invoked
+// through a link named dcal, dholydays, or dconvert, it behaves as the cal,
+// holydays, or convert command, respectively.
+//
 // Description
 //
 // ddate prints the date Discordian date format.
@@ -72,7 +246,7 @@
 //
 //     $ ddate +"Today is %{%A, the %e of %B%}, %Y. %N%nCelebrate %H!" 26 9 1995
 //     > Today is Prickle-Prickle, the 50th of Bureaucracy, 3161.
-//     > Celebrate Bureflux
+//     > Celebrate Bureflux!
 //
 // If the date is February 29th, the Special St. Tib's Day formatters are used
 // to display "St. Tib's Day".
@@ -80,10 +254,61 @@
 //     $ ddate +"Today is %{%A, the %e of %B%}, %Y. %N%nCelebrate %H!" 29 2 1996
 //     > Today is St. Tib's Day, 3162.
 //
+// Many dates can be converted at once from the standard input.
+//
+//     $ printf '26 9 1995\n2000-02-29\n' | ddate +"%{%e of %B%}, %Y" -
+//     > 50th of Bureaucracy, 3161
+//     > St. Tib's Day, 3166
+//
+// The date can be printed as JSON for other programs.
+//
+//     $ ddate --output json 26 9 1995
+//     > {"gregorian":"1995-09-26","yold":3161,"season":"Bureaucracy","day":50,...}
+//
+// A column of Discordian dates can be added to a spreadsheet.
+//
+//     $ printf 'id,date\n1,1995-09-26\n' | ddate csv --column date --append +%Y
+//     > id,date,%Y
+//     > 1,1995-09-26,3161
+//
+// The holydays can be imported into a calendar app.
+//
+//     $ ddate ics 3192 3200 > holydays.ics
+//
+// Other programs can convert dates over HTTP.
+//
+//     $ ddate serve &
+//     $ curl 'localhost:8023/date/1995-09-26?format=%25e+%25B'
+//     > 50th Bureaucracy
+//
+// The Gregorian date and time can be mixed into a format.
+//
+//     $ ddate -d "1995-09-26 21:05" +"%A, %B %d %G(%Y-%m-%d %H:%M)"
+//     > Prickle-Prickle, Bureaucracy 50 1995-09-26 21:05
+//
+// A Discordian date can be converted back to the Gregorian calendar.
+//
+//     $ ddate --reverse Confusion 23, 3193
+//     > 2027-06-18
+//
+// The calendar of the current season highlights today.
+//
+//     $ ddate cal
+//     >   Bureaucracy 3192
+//     >  SM  BT  PD  PP  SO
+//     >                   1
+//     >   2   3   4   5*  6
+//     >  ...
+//     >  67  68  69  70  71
+//     > [72] 73
+//
 // Bugs
 //
-// ddate will produce undefined behaviour if asked to produce the date for St.
-// Tib's Day with out the %{ and %} delimiters.
+// St. Tib's Day is not part of any week or season, so it should be formatted
+// with the %{ and %} delimiters. Outside of them, %A and %a format "St. Tib's
+// Day" and "Tib", %B and %b format "Chaos" and "Chs", the season in which St.
+// Tib's Day falls, and %d and %e format nothing. With --strict, using any of
+// these directives outside of the delimiters on St. Tib's Day is an error.
 //
 // Author
 //
@@ -97,3 +322,5 @@
 // Public Domain. All Rites Reversed.
 //
 package main
+
+//go:generate go run ./internal/gendoc
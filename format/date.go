@@ -0,0 +1,237 @@
+package format
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// yoldOffset is the difference between a Gregorian year and the
+	// corresponding Year of Our Lady of Discord.
+	yoldOffset = 1166
+
+	// DaysPerSeason is the number of days in every Discordian season.
+	DaysPerSeason = 73
+
+	// DaysPerWeek is the number of days in every Discordian week.
+	DaysPerWeek = 5
+
+	// tibsYearDay is the zero based day of the Gregorian year on which St.
+	// Tib's Day (February 29th) falls.
+	tibsYearDay = 31 + 28
+)
+
+// Date is a date in the Discordian Calendar.
+//
+// On St. Tib's Day, which falls between Chaos 59 and Chaos 60 in leap years,
+// the date is outside of the regular weeks and seasons. The Season is Chaos,
+// the Day and YearDay are zero, and the Weekday is -1.
+type Date struct {
+	YOLD    int     // year of our lady of discord
+	Season  Season  // season of the year
+	Day     int     // one based day of the season
+	Weekday Weekday // day of the week
+	YearDay int     // one based day of the year, not counting St. Tib's Day
+	TibsDay bool    // whether the date is St. Tib's Day
+}
+
+// NewDate converts the Gregorian date of the given time to a Discordian date.
+//
+// Only the year, month, and day of the time in its own location are used, the
+// time of day is ignored.
+func NewDate(t time.Time) Date {
+	year, month, day := t.Date()
+	yday := t.YearDay() - 1
+
+	if month == time.February && day == 29 {
+		return Date{YOLD: year + yoldOffset, Season: Chaos, Weekday: -1, TibsDay: true}
+	}
+
+	// St. Tib's Day is not part of any week or season, so the days after it
+	// are counted as if it never happened.
+	if isLeap(year) && yday > tibsYearDay {
+		yday--
+	}
+
+	return DateOf(year+yoldOffset, Season(yday/DaysPerSeason), yday%DaysPerSeason+1)
+}
+
+// DateOf returns the Discordian date of the given day of the season in the
+// given YOLD. The date is not validated, use Date.Time to check that it exists.
+func DateOf(yold int, season Season, day int) Date {
+	yday := int(season)*DaysPerSeason + day - 1
+
+	return Date{
+		YOLD:    yold,
+		Season:  season,
+		Day:     day,
+		Weekday: Weekday(yday % DaysPerWeek),
+		YearDay: yday + 1,
+	}
+}
+
+// Holyday returns the holyday of the date, if any.
+//
+// The apostle holydays fall on the 5th day of every season and the season
+// holydays fall on the 50th, St. Tib's Day is not a holyday in this sense.
+func (d Date) Holyday() (holyday Holyday, ok bool) {
+	switch {
+	case d.TibsDay:
+		return 0, false
+	case d.Day == 5:
+		return Holyday(d.Season), true
+	case d.Day == 50:
+		return Holyday(d.Season) + Chaoflux, true
+	default:
+		return 0, false
+	}
+}
+
+// String returns the date in the default format, i.e. "Sweetmorn, Chaos 1,
+// 3166 YOLD" or "St. Tib's Day, 3166 YOLD".
+func (d Date) String() string {
+	if d.TibsDay {
+		return English.TibsDay + ", " + strconv.Itoa(d.YOLD) + " YOLD"
+	}
+
+	return d.Weekday.String() + ", " + d.Season.String() + " " + strconv.Itoa(d.Day) + ", " + strconv.Itoa(d.YOLD) + " YOLD"
+}
+
+// isLeap reports whether the given Gregorian year is a leap year.
+func isLeap(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+// XDay is the canonical date of X-Day, on which the men from planet X will
+// arrive. The countdown of the %X directive uses this date unless another one
+// is given with WithXDay.
+var XDay = time.Date(8661, time.July, 5, 0, 0, 0, 0, time.UTC)
+
+// DaysUntil returns the number of whole days from the date of t until the date
+// of u, ignoring the time of day and the time zones of both.
+func DaysUntil(t, u time.Time) int {
+	const secondsPerDay = 24 * 60 * 60
+
+	ty, tm, td := t.Date()
+	uy, um, ud := u.Date()
+
+	from := time.Date(ty, tm, td, 0, 0, 0, 0, time.UTC).Unix()
+	to := time.Date(uy, um, ud, 0, 0, 0, 0, time.UTC).Unix()
+
+	return int((to - from) / secondsPerDay)
+}
+
+// Time returns the Gregorian date of the Discordian date, at midnight in the
+// given location.
+//
+// Only the YOLD, Season, Day, and TibsDay fields are used. An error is returned
+// if the day is outside of the season, or if the date is St. Tib's Day in a
+// YOLD which does not correspond to a Gregorian leap year.
+func (d Date) Time(loc *time.Location) (time.Time, error) {
+	year := d.YOLD - yoldOffset
+
+	if d.TibsDay {
+		if !isLeap(year) {
+			return time.Time{}, fmt.Errorf("no St. Tib's Day in %d YOLD", d.YOLD)
+		}
+
+		return time.Date(year, time.February, 29, 0, 0, 0, 0, loc), nil
+	}
+
+	if d.Season < Chaos || d.Season > TheAftermath {
+		return time.Time{}, fmt.Errorf("invalid season %d", d.Season)
+	}
+
+	if d.Day < 1 || d.Day > DaysPerSeason {
+		return time.Time{}, fmt.Errorf("day %d out of range for %s", d.Day, d.Season)
+	}
+
+	yday := int(d.Season)*DaysPerSeason + d.Day - 1
+
+	// Skip over St. Tib's Day, which is not counted in the seasons.
+	if isLeap(year) && yday >= tibsYearDay {
+		yday++
+	}
+
+	return time.Date(year, time.January, yday+1, 0, 0, 0, 0, loc), nil
+}
+
+// ParseDate parses a Discordian date written as the season, day, and YOLD, such
+// as "Confusion 23, 3193", or as St. Tib's Day and YOLD, such as "St. Tib's
+// Day, 3162".
+//
+// Seasons may be given by their full or abbreviated name and case is ignored.
+// An error is returned if the date does not exist, see Date.Time.
+func ParseDate(s string) (d Date, err error) {
+	fields := strings.Fields(strings.ReplaceAll(s, ",", " "))
+
+	if len(fields) < 2 {
+		return Date{}, fmt.Errorf("invalid Discordian date %q", s)
+	}
+
+	if d.YOLD, err = strconv.Atoi(fields[len(fields)-1]); err != nil {
+		return Date{}, fmt.Errorf("invalid YOLD in Discordian date %q", s)
+	}
+
+	fields = fields[:len(fields)-1]
+
+	if isTibsDay(fields) {
+		d.Season, d.Weekday, d.TibsDay = Chaos, -1, true
+	} else if len(fields) < 2 {
+		return Date{}, fmt.Errorf("invalid Discordian date %q", s)
+	} else if d.Day, err = strconv.Atoi(fields[len(fields)-1]); err != nil {
+		return Date{}, fmt.Errorf("invalid day in Discordian date %q", s)
+	} else if d.Season, err = ParseSeason(strings.Join(fields[:len(fields)-1], " ")); err != nil {
+		return Date{}, err
+	} else {
+		d = DateOf(d.YOLD, d.Season, d.Day)
+	}
+
+	if _, err = d.Time(time.UTC); err != nil {
+		return Date{}, err
+	}
+
+	return d, nil
+}
+
+// isTibsDay reports whether the fields spell out St. Tib's Day, ignoring case
+// and punctuation (i.e. "St. Tib's Day", "st tibs day", or "St. Tibs").
+func isTibsDay(fields []string) bool {
+	name := strings.ToLower(strings.Join(fields, " "))
+	name = strings.NewReplacer(".", "", "'", "").Replace(name)
+
+	return name == "st tibs day" || name == "st tibs" || name == "saint tibs day"
+}
+
+// ParseSeason parses the full or abbreviated English name of a season, ignoring
+// case. The leading "The" of "The Aftermath" is optional.
+func ParseSeason(s string) (Season, error) {
+	for season := Chaos; season <= TheAftermath; season++ {
+		full, abbr := season.String(), season.Abbr()
+
+		if strings.EqualFold(s, full) || strings.EqualFold(s, abbr) || strings.EqualFold("The "+s, full) {
+			return season, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown season %q", s)
+}
+
+// Holydays returns the dates of the holydays in the YOLD, in order, including
+// St. Tib's Day if the YOLD has one.
+func Holydays(yold int) []Date {
+	var dates []Date
+
+	for season := Chaos; season <= TheAftermath; season++ {
+		dates = append(dates, DateOf(yold, season, 5), DateOf(yold, season, 50))
+
+		// St. Tib's Day falls between Chaos 59 and Chaos 60
+		if tibs := (Date{YOLD: yold, Weekday: -1, TibsDay: true}); season == Chaos && isLeap(yold-yoldOffset) {
+			dates = append(dates, tibs)
+		}
+	}
+
+	return dates
+}
@@ -0,0 +1,295 @@
+package format
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+)
+
+func TestNewDate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string    // name of the test case
+		have    time.Time // input Gregorian date
+		want    Date      // expected Discordian date
+		holyday Holyday   // expected holyday
+		holy    bool      // whether the date is expected to be a holyday
+		str     string    // expected string representation
+	}{
+		{
+			name: "First Day Of The Year",
+			have: time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+			want: Date{YOLD: 3166, Season: Chaos, Day: 1, Weekday: Sweetmorn, YearDay: 1},
+			str:  "Sweetmorn, Chaos 1, 3166 YOLD",
+		},
+		{
+			name:    "Mungday",
+			have:    time.Date(2000, time.January, 5, 23, 59, 59, 0, time.UTC),
+			want:    Date{YOLD: 3166, Season: Chaos, Day: 5, Weekday: SettingOrange, YearDay: 5},
+			holyday: Mungday,
+			holy:    true,
+			str:     "Setting Orange, Chaos 5, 3166 YOLD",
+		},
+		{
+			name: "St Tibs Day",
+			have: time.Date(2000, time.February, 29, 0, 0, 0, 0, time.UTC),
+			want: Date{YOLD: 3166, Season: Chaos, Weekday: -1, TibsDay: true},
+			str:  "St. Tib's Day, 3166 YOLD",
+		},
+		{
+			name: "Day After St Tibs Day",
+			have: time.Date(2000, time.March, 1, 0, 0, 0, 0, time.UTC),
+			want: Date{YOLD: 3166, Season: Chaos, Day: 60, Weekday: SettingOrange, YearDay: 60},
+			str:  "Setting Orange, Chaos 60, 3166 YOLD",
+		},
+		{
+			name:    "Bureflux",
+			have:    time.Date(1995, time.September, 26, 0, 0, 0, 0, time.UTC),
+			want:    Date{YOLD: 3161, Season: Bureaucracy, Day: 50, Weekday: PricklePrickle, YearDay: 269},
+			holyday: Bureflux,
+			holy:    true,
+			str:     "Prickle-Prickle, Bureaucracy 50, 3161 YOLD",
+		},
+		{
+			name: "Last Day Of A Common Year",
+			have: time.Date(1999, time.December, 31, 0, 0, 0, 0, time.UTC),
+			want: Date{YOLD: 3165, Season: TheAftermath, Day: 73, Weekday: SettingOrange, YearDay: 365},
+			str:  "Setting Orange, The Aftermath 73, 3165 YOLD",
+		},
+		{
+			name: "Last Day Of A Leap Year",
+			have: time.Date(2000, time.December, 31, 0, 0, 0, 0, time.UTC),
+			want: Date{YOLD: 3166, Season: TheAftermath, Day: 73, Weekday: SettingOrange, YearDay: 365},
+			str:  "Setting Orange, The Aftermath 73, 3166 YOLD",
+		},
+	}
+
+	for _, test := range tests {
+		// shadow loop var to prevent nasty bugs
+		test := test
+
+		// trim whitespace from name of test case
+		name := strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+
+			return r
+		}, test.name)
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act
+			date := NewDate(test.have)
+			holyday, holy := date.Holyday()
+
+			// Assert
+			if have, want := date, test.want; have != want {
+				t.Errorf("date: have %+v, want %+v", have, want)
+			}
+
+			if have, want := holy, test.holy; have != want {
+				t.Errorf("holy: have %t, want %t", have, want)
+			} else if have, want := holyday, test.holyday; holy && have != want {
+				t.Errorf("holyday: have %s, want %s", have, want)
+			}
+
+			if have, want := date.String(), test.str; have != want {
+				t.Errorf("string: have %q, want %q", have, want)
+			}
+		})
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string    // name of the test case
+		have string    // input Discordian date
+		want time.Time // expected Gregorian date (if zero expect err)
+	}{
+		{
+			name: "Full Season Name",
+			have: "Confusion 23, 3193",
+			want: time.Date(2027, time.June, 18, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "Abbreviated Season Name",
+			have: "cfn 23 3193",
+			want: time.Date(2027, time.June, 18, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "Season Name With Article",
+			have: "The Aftermath 73, 3166",
+			want: time.Date(2000, time.December, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "Season Name Without Article",
+			have: "aftermath 73, 3166",
+			want: time.Date(2000, time.December, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "Before St Tibs Day",
+			have: "Chaos 59, 3166",
+			want: time.Date(2000, time.February, 28, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "After St Tibs Day",
+			have: "Chaos 60, 3166",
+			want: time.Date(2000, time.March, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "St Tibs Day",
+			have: "St. Tib's Day, 3166",
+			want: time.Date(2000, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "St Tibs Day Without Punctuation",
+			have: "st tibs day 3162",
+			want: time.Date(1996, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "St Tibs Day In A Common Year",
+			have: "St. Tib's Day, 3165",
+		},
+		{
+			name: "St Tibs Day In A Century Year",
+			have: "St. Tib's Day, 3066",
+		},
+		{
+			name: "Day Zero",
+			have: "Chaos 0, 3166",
+		},
+		{
+			name: "Day Out Of Range",
+			have: "Chaos 74, 3166",
+		},
+		{
+			name: "Unknown Season",
+			have: "Summer 1, 3166",
+		},
+		{
+			name: "Missing Day",
+			have: "Chaos 3166",
+		},
+		{
+			name: "Invalid YOLD",
+			have: "Chaos 1, MMXXII",
+		},
+		{
+			name: "Empty",
+			have: "",
+		},
+	}
+
+	for _, test := range tests {
+		// shadow loop var to prevent nasty bugs
+		test := test
+
+		// trim whitespace from name of test case
+		name := strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+
+			return r
+		}, test.name)
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act
+			date, err := ParseDate(test.have)
+
+			// Assert
+			if test.want.IsZero() {
+				if err == nil {
+					t.Fatalf("error: have nil, want error")
+				}
+
+				return // don't keep testing, expected failure detected
+			}
+
+			if err != nil {
+				t.Fatalf("error: have %q, want nil", err)
+			}
+
+			have, err := date.Time(time.UTC)
+			if err != nil {
+				t.Fatalf("time error: have %q, want nil", err)
+			}
+
+			if want := test.want; !have.Equal(want) {
+				t.Errorf("time: have %s, want %s", have, want)
+			}
+
+			if have, want := date, NewDate(test.want); have != want {
+				t.Errorf("date: have %+v, want %+v", have, want)
+			}
+		})
+	}
+}
+
+func TestDateTimeRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	start := time.Date(1995, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2001, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	for want := start; want.Before(end); want = want.AddDate(0, 0, 1) {
+		// Act
+		have, err := NewDate(want).Time(time.UTC)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("error for %s: have %q, want nil", want, err)
+		}
+
+		if !have.Equal(want) {
+			t.Fatalf("time: have %s, want %s", have, want)
+		}
+	}
+}
+
+func TestHolydays(t *testing.T) {
+	t.Parallel()
+
+	tests := map[int]int{
+		3165: 10, // common year
+		3166: 11, // leap year
+		3066: 10, // century year
+		3566: 11, // 400th year
+	}
+
+	for yold, want := range tests {
+		// Act
+		dates := Holydays(yold)
+
+		// Assert
+		if have := len(dates); have != want {
+			t.Errorf("holydays in %d: have %d, want %d", yold, have, want)
+		}
+
+		for i, date := range dates {
+			have, err := date.Time(time.UTC)
+			if err != nil {
+				t.Fatalf("time error: have %q, want nil", err)
+			}
+
+			if _, ok := date.Holyday(); !ok && !date.TibsDay {
+				t.Errorf("holyday %s: not a holyday", date)
+			}
+
+			if i > 0 {
+				if prev, _ := dates[i-1].Time(time.UTC); !prev.Before(have) {
+					t.Errorf("holyday %s: not after %s", date, dates[i-1])
+				}
+			}
+		}
+	}
+}
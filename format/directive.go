@@ -1,5 +1,11 @@
 package format
 
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
 // Directive specifies a formatting function within a format string.
 type Directive string
 
@@ -13,19 +19,19 @@ const (
 	// Formats the full name of the season (i.e. Chaos).
 	FullSeasonDirective Directive = "%B"
 
-	// Formats the abbreviated name of the day of the week (i.e. Chs).
+	// Formats the abbreviated name of the season (i.e. Chs).
 	AbbrSeasonDirective Directive = "%b"
 
-	// Formats the ordinal number of the day in the season (i.e. 23).
+	// Formats the number of the day in the season (i.e. 23).
 	OrdinalDayDirective Directive = "%d"
 
-	// Formats the cardinal number of the day in the season (i.e. 23rd).
+	// Formats the ordinal number of the day in the season (i.e. 23rd).
 	CardinalDayDirective Directive = "%e"
 
-	// Formats the ordinal year of our lady of of discord (i.e. 3161).
+	// Formats the year of our lady of discord (i.e. 3161).
 	OrdinalYearDirective Directive = "%Y"
 
-	// Formats the cardinal year of our lady of discord (i.e. 3161st).
+	// Formats the ordinal year of our lady of discord (i.e. 3161st).
 	CardinalYearDirective Directive = "%y"
 
 	// Formats the name of the current Holyday, if any (i.e. Confuflux).
@@ -44,18 +50,275 @@ const (
 	// Formats a literal percent sign character.
 	PercentDirective Directive = "%%"
 
-	// Formats the cardinal number of days remaining until X-Day from the given
-	// date.
+	// Formats the number of days remaining until X-Day from the given date.
 	XDayDirective Directive = "%X"
 
 	// Begins a special block to enclose part of the string.
 	StartTibsDayDirective Directive = "%{"
 
-	// Closes a special block opened by %}, the enclosed the part of the string
-	// is replaced with the words "St. Tib's Day" if the current day is St.
-	// Tib's Day.
+	// Closes a special block opened by %{, the enclosed part of the string is
+	// replaced with the words "St. Tib's Day" if the current day is St. Tib's
+	// Day.
 	EndTibsDayDirective Directive = "%}"
 
 	// Try it and see...
 	MagicDirective Directive = "%."
+
+	// Formats the Gregorian date of the same instant according to the
+	// strftime(3) layout enclosed in parentheses (i.e. %G(%Y-%m-%d %H:%M)).
+	GregorianDirective Directive = "%G"
+)
+
+// Category groups related directives in the documentation.
+type Category string
+
+const (
+	WeekdayCategory   Category = "weekday"
+	SeasonCategory    Category = "season"
+	DayCategory       Category = "day"
+	YearCategory      Category = "year"
+	HolydayCategory   Category = "holyday"
+	TibsDayCategory   Category = "St. Tib's Day"
+	XDayCategory      Category = "X-Day"
+	GregorianCategory Category = "Gregorian"
+	TextCategory      Category = "text"
+	MagicCategory     Category = "magic"
+	CustomCategory    Category = "user-defined"
 )
+
+// DirectiveInfo describes a directive. It is the single source of the
+// documentation of the directives, in the help, the package documentation,
+// and the man page of ddate.
+type DirectiveInfo struct {
+	Directive   Directive `json:"directive"`          // code of the directive
+	Argument    string    `json:"argument,omitempty"` // syntax of its argument, if any
+	Description string    `json:"description"`        // what it formats, as a verb phrase
+	Example     string    `json:"example,omitempty"`  // example of its output, if any
+	Category    Category  `json:"category"`           // group of related directives
+}
+
+// String returns the directive together with the syntax of its argument.
+func (d DirectiveInfo) String() string {
+	return string(d.Directive) + d.Argument
+}
+
+// Summary returns the description of the directive together with its example.
+func (d DirectiveInfo) Summary() string {
+	if d.Example == "" {
+		return d.Description
+	}
+
+	return fmt.Sprintf("%s (i.e. %s)", d.Description, d.Example)
+}
+
+// registry describes every directive understood by the parser, in the order
+// of the documentation. The examples are those of September 26th, 1995 at 21:05
+// UTC, which is Bureflux.
+var registry = []DirectiveInfo{
+	{
+		Directive:   FullWeekdayDirective,
+		Description: "formats the full name of the day of the week",
+		Example:     "Prickle-Prickle",
+		Category:    WeekdayCategory,
+	},
+	{
+		Directive:   AbbrWeekdayDirective,
+		Description: "formats the abbreviated name of the day of the week",
+		Example:     "PP",
+		Category:    WeekdayCategory,
+	},
+	{
+		Directive:   FullSeasonDirective,
+		Description: "formats the full name of the season",
+		Example:     "Bureaucracy",
+		Category:    SeasonCategory,
+	},
+	{
+		Directive:   AbbrSeasonDirective,
+		Description: "formats the abbreviated name of the season",
+		Example:     "Bcy",
+		Category:    SeasonCategory,
+	},
+	{
+		Directive:   OrdinalDayDirective,
+		Description: "formats the number of the day in the season",
+		Example:     "50",
+		Category:    DayCategory,
+	},
+	{
+		Directive:   CardinalDayDirective,
+		Description: "formats the ordinal number of the day in the season",
+		Example:     "50th",
+		Category:    DayCategory,
+	},
+	{
+		Directive:   OrdinalYearDirective,
+		Description: "formats the year of our lady of discord",
+		Example:     "3161",
+		Category:    YearCategory,
+	},
+	{
+		Directive:   CardinalYearDirective,
+		Description: "formats the ordinal year of our lady of discord",
+		Example:     "3161st",
+		Category:    YearCategory,
+	},
+	{
+		Directive:   HolydayDirective,
+		Description: "formats the name of the current Holyday, if any",
+		Example:     "Bureflux",
+		Category:    HolydayCategory,
+	},
+	{
+		Directive:   NonHolidayDirective,
+		Description: "is a magic code to prevent the remainder of the format string being printed unless the date is a Holyday",
+		Category:    HolydayCategory,
+	},
+	{
+		Directive:   NewlineDirective,
+		Description: "formats a newline character",
+		Category:    TextCategory,
+	},
+	{
+		Directive:   TabDirective,
+		Description: "formats a tab character",
+		Category:    TextCategory,
+	},
+	{
+		Directive:   PercentDirective,
+		Description: "formats a literal percent sign character",
+		Example:     "%",
+		Category:    TextCategory,
+	},
+	{
+		Directive:   XDayDirective,
+		Description: "formats the number of days remaining until X-Day from the given date",
+		Example:     "2434624",
+		Category:    XDayCategory,
+	},
+	{
+		Directive:   StartTibsDayDirective,
+		Description: "begins the part of the string which is replaced with the words \"St. Tib's Day\" if the current day is St. Tib's Day",
+		Category:    TibsDayCategory,
+	},
+	{
+		Directive:   EndTibsDayDirective,
+		Description: "ends the part of the string begun by %{",
+		Category:    TibsDayCategory,
+	},
+	{
+		Directive:   GregorianDirective,
+		Argument:    "(...)",
+		Description: "formats the Gregorian date and time of day of the same instant, according to the strftime(3) layout in the parentheses",
+		Example:     "1995-09-26 21:05",
+		Category:    GregorianCategory,
+	},
+	{
+		Directive:   MagicDirective,
+		Description: "Try it and see...",
+		Category:    MagicCategory,
+	},
+}
+
+// registryMutex guards the registry, the directives, and the functions of the
+// user-defined directives.
+var registryMutex sync.RWMutex
+
+// custom are the formatting functions of the user-defined directives.
+var custom = map[Directive]func(Date) string{}
+
+// Directives returns the descriptions of every directive, in the order of the
+// documentation, followed by the user-defined directives in the order they
+// were registered.
+func Directives() []DirectiveInfo {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	return append([]DirectiveInfo(nil), registry...)
+}
+
+// Register adds a user-defined directive, which is formatted by the given
+// function, so that programs can extend the format strings with their own
+// directives (i.e. %P for the patron apostle of the season). It panics if the
+// directive cannot be registered, see RegisterDirective.
+func Register(code Directive, fn func(Date) string) {
+	info := DirectiveInfo{
+		Directive:   code,
+		Description: "formats a user-defined directive",
+		Category:    CustomCategory,
+	}
+
+	if err := RegisterDirective(info, fn); err != nil {
+		panic("format: Register: " + err.Error())
+	}
+}
+
+// RegisterDirective adds a user-defined directive like Register, with the
+// description of the directive for its documentation. The category defaults
+// to CustomCategory.
+//
+// The directive must be a percent sign followed by a single ASCII letter or
+// punctuation character which is not a built-in directive, nor a flag or a
+// digit of a width. Registering a user-defined directive again replaces it.
+// Layouts which have already been parsed keep the function they were parsed
+// with.
+func RegisterDirective(info DirectiveInfo, fn func(Date) string) error {
+	code := info.Directive
+
+	if len(code) != 2 || code[0] != '%' || code[1] <= ' ' || code[1] > '~' || code[1] == '(' || code[1] == ')' {
+		return fmt.Errorf("invalid directive %q, want %% and a letter or punctuation", code)
+	} else if strings.IndexByte(directiveFlags, code[1]) >= 0 || '0' <= code[1] && code[1] <= '9' {
+		return fmt.Errorf("invalid directive %q, %c is a flag or a width", code, code[1])
+	} else if fn == nil {
+		return fmt.Errorf("nil function for directive %s", code)
+	} else if info.Argument != "" {
+		return fmt.Errorf("directive %s cannot take an argument", code)
+	}
+
+	if info.Category == "" {
+		info.Category = CustomCategory
+	}
+
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	if _, ok := custom[code]; !ok && directives[code] {
+		return fmt.Errorf("directive %s is built in", code)
+	}
+
+	for i := range registry {
+		if registry[i].Directive == code {
+			registry = append(registry[:i:i], registry[i+1:]...)
+			break
+		}
+	}
+
+	registry = append(registry, info)
+	directives[code] = true
+	custom[code] = fn
+
+	return nil
+}
+
+// UnregisterDirective removes a user-defined directive, i.e. so that tests do
+// not leak their directives into each other. Built-in directives are never
+// removed, and layouts which have already been parsed keep the function they
+// were parsed with.
+func UnregisterDirective(code Directive) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	if _, ok := custom[code]; !ok {
+		return // never remove a built-in directive
+	}
+
+	for i := range registry {
+		if registry[i].Directive == code {
+			registry = append(registry[:i:i], registry[i+1:]...)
+			break
+		}
+	}
+
+	delete(directives, code)
+	delete(custom, code)
+}
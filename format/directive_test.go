@@ -0,0 +1,222 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+)
+
+func TestDirectives(t *testing.T) {
+	t.Parallel()
+
+	// the examples of the registry are those of this date
+	date := time.Date(1995, time.September, 26, 21, 5, 0, 0, time.UTC)
+
+	for _, info := range Directives() {
+		// shadow loop var to prevent nasty bugs
+		info := info
+
+		// trim whitespace from name of test case
+		name := strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+
+			return r
+		}, string(info.Category)+info.String())
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			layout := info.String()
+
+			switch info.Directive {
+			case StartTibsDayDirective:
+				layout += string(EndTibsDayDirective)
+			case EndTibsDayDirective:
+				layout = string(StartTibsDayDirective) + layout
+			case GregorianDirective:
+				layout = string(info.Directive) + "(%Y-%m-%d %H:%M)"
+			}
+
+			// Act
+			have, err := Format(layout, date)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("error: have %q, want nil", err)
+			}
+
+			if info.Description == "" {
+				t.Errorf("description: have %q, want non-empty", info.Description)
+			}
+
+			if want := info.Example; want != "" && have != want {
+				t.Errorf("example: have %q, want %q", have, want)
+			}
+		})
+	}
+}
+
+func TestRegisterDirective(t *testing.T) {
+	t.Parallel()
+
+	// patron is the patron apostle of the season of the date
+	patron := func(d Date) string {
+		return []string{"Hung Mung", "Dr. Van Van Mojo", "Sri Syadasti", "Zarathud", "The Elder Malaclypse"}[d.Season]
+	}
+
+	tests := []struct {
+		name   string            // name of the test case
+		info   DirectiveInfo     // directive to register
+		fn     func(Date) string // formatting function of the directive
+		layout string            // format string using the directive
+		want   string            // expected output, or error
+	}{
+		{
+			name:   "Patron Apostle",
+			info:   DirectiveInfo{Directive: "%P", Description: "formats the patron apostle of the season"},
+			fn:     patron,
+			layout: "%B is the season of %P",
+			want:   "Bureaucracy is the season of Zarathud",
+		},
+		{
+			name:   "Inside St Tibs Day Block",
+			info:   DirectiveInfo{Directive: "%Q", Description: "formats the day of the year"},
+			fn:     func(d Date) string { return fmt.Sprint(d.YearDay) },
+			layout: "%{day %Q%}",
+			want:   "day 269",
+		},
+		{
+			name: "Built In",
+			info: DirectiveInfo{Directive: FullWeekdayDirective},
+			fn:   patron,
+			want: "directive %A is built in",
+		},
+		{
+			name: "Too Long",
+			info: DirectiveInfo{Directive: "%PP"},
+			fn:   patron,
+			want: `invalid directive "%PP", want % and a letter or punctuation`,
+		},
+		{
+			name: "Parenthesis",
+			info: DirectiveInfo{Directive: "%("},
+			fn:   patron,
+			want: `invalid directive "%(", want % and a letter or punctuation`,
+		},
+		{
+			name: "Flag",
+			info: DirectiveInfo{Directive: "%^"},
+			fn:   patron,
+			want: `invalid directive "%^", ^ is a flag or a width`,
+		},
+		{
+			name: "Digit",
+			info: DirectiveInfo{Directive: "%5"},
+			fn:   patron,
+			want: `invalid directive "%5", 5 is a flag or a width`,
+		},
+		{
+			name: "Nil Function",
+			info: DirectiveInfo{Directive: "%R"},
+			want: "nil function for directive %R",
+		},
+	}
+
+	for _, test := range tests {
+		// shadow loop var to prevent nasty bugs
+		test := test
+
+		// trim whitespace from name of test case
+		name := strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+
+			return r
+		}, test.name)
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			date := time.Date(1995, time.September, 26, 21, 5, 0, 0, time.UTC)
+
+			// Act
+			err := RegisterDirective(test.info, test.fn)
+			t.Cleanup(func() { UnregisterDirective(test.info.Directive) })
+
+			// Assert
+			if test.layout == "" {
+				if err == nil || err.Error() != test.want {
+					t.Fatalf("error: have %v, want %q", err, test.want)
+				}
+
+				return
+			} else if err != nil {
+				t.Fatalf("error: have %q, want nil", err)
+			}
+
+			if have, err := Format(test.layout, date); err != nil {
+				t.Fatalf("error: have %q, want nil", err)
+			} else if have != test.want {
+				t.Errorf("output: have %q, want %q", have, test.want)
+			}
+
+			var info DirectiveInfo
+			for _, info = range Directives() {
+				if info.Directive == test.info.Directive {
+					break
+				}
+			}
+
+			if have, want := info.Category, CustomCategory; have != want {
+				t.Errorf("category: have %q, want %q", have, want)
+			}
+		})
+	}
+}
+
+func TestUnregisterDirective(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	if err := RegisterDirective(DirectiveInfo{Directive: "%@", Description: "formats an at sign"}, func(Date) string { return "@" }); err != nil {
+		t.Fatalf("error: have %q, want nil", err)
+	}
+
+	layout, err := Parse("%A %@")
+	if err != nil {
+		t.Fatalf("error: have %q, want nil", err)
+	}
+
+	// Act
+	UnregisterDirective("%@")
+	UnregisterDirective(FullWeekdayDirective)
+
+	// Assert
+	if _, err := Parse("%@"); err == nil {
+		t.Errorf("error: have nil, want unknown directive %%@")
+	}
+
+	if _, err := Parse("%A"); err != nil {
+		t.Errorf("error: have %q, want nil", err)
+	}
+
+	for _, info := range Directives() {
+		if info.Directive == "%@" {
+			t.Errorf("directives: have %%@, want it removed")
+		}
+	}
+
+	date := time.Date(1995, time.September, 26, 0, 0, 0, 0, time.UTC)
+	if have, err := layout.Format(date); err != nil {
+		t.Errorf("error: have %q, want nil", err)
+	} else if want := "Prickle-Prickle @"; have != want {
+		t.Errorf("output: have %q, want %q", have, want)
+	}
+}
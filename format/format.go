@@ -0,0 +1,18 @@
+package format
+
+import "time"
+
+// Format returns a textual representation of the Discordian date of the given
+// time, formatted according to the directives in the given format string.
+//
+// Any text in the format string which is not part of a directive is copied to
+// the output unchanged. To format many dates with the same format string, use
+// Parse to compile it once instead. See Layout.Format for the options.
+func Format(format string, t time.Time, opts ...Option) (string, error) {
+	l, err := Parse(format)
+	if err != nil {
+		return "", err
+	}
+
+	return l.Format(t, opts...)
+}
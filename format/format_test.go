@@ -0,0 +1,323 @@
+package format
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+)
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string    // name of the test case
+		format string    // input format string
+		date   time.Time // input date
+		opts   []Option  // formatting options
+		want   string    // expected output
+		err    bool      // whether an error is expected
+	}{
+		{
+			name:   "Empty Format",
+			format: "",
+			date:   time.Date(1995, time.September, 26, 0, 0, 0, 0, time.UTC),
+			want:   "",
+		},
+		{
+			name:   "Literal Text",
+			format: "No directives here",
+			date:   time.Date(1995, time.September, 26, 0, 0, 0, 0, time.UTC),
+			want:   "No directives here",
+		},
+		{
+			name:   "Default Format",
+			format: "%A, %B %d, %Y YOLD",
+			date:   time.Date(1995, time.September, 26, 0, 0, 0, 0, time.UTC),
+			want:   "Prickle-Prickle, Bureaucracy 50, 3161 YOLD",
+		},
+		{
+			name:   "Abbreviations",
+			format: "%a %b",
+			date:   time.Date(2022, time.January, 5, 0, 0, 0, 0, time.UTC),
+			want:   "SO Chs",
+		},
+		{
+			name:   "Cardinal Numbers",
+			format: "%e %y",
+			date:   time.Date(1995, time.January, 23, 0, 0, 0, 0, time.UTC),
+			want:   "23rd 3161st",
+		},
+		{
+			name:   "Cardinal Teens",
+			format: "%e",
+			date:   time.Date(1995, time.January, 12, 0, 0, 0, 0, time.UTC),
+			want:   "12th",
+		},
+		{
+			name:   "First Day Of The Year",
+			format: "%A, %B %d, %Y",
+			date:   time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+			want:   "Sweetmorn, Chaos 1, 3166",
+		},
+		{
+			name:   "Last Day Of A Leap Year",
+			format: "%A, %B %d, %Y",
+			date:   time.Date(2000, time.December, 31, 0, 0, 0, 0, time.UTC),
+			want:   "Setting Orange, The Aftermath 73, 3166",
+		},
+		{
+			name:   "Day After St Tibs Day",
+			format: "%A, %B %d",
+			date:   time.Date(1996, time.March, 1, 0, 0, 0, 0, time.UTC),
+			want:   "Setting Orange, Chaos 60",
+		},
+		{
+			name:   "Apostle Holyday",
+			format: "%H",
+			date:   time.Date(1995, time.May, 31, 0, 0, 0, 0, time.UTC),
+			want:   "Syaday",
+		},
+		{
+			name:   "Season Holyday",
+			format: "Today is %{%A, the %e of %B%}, %Y. %N%nCelebrate %H!",
+			date:   time.Date(1995, time.September, 26, 0, 0, 0, 0, time.UTC),
+			want:   "Today is Prickle-Prickle, the 50th of Bureaucracy, 3161. \nCelebrate Bureflux!",
+		},
+		{
+			name:   "Not A Holyday",
+			format: "Today is %{%A, the %e of %B%}, %Y. %N%nCelebrate %H!",
+			date:   time.Date(1995, time.September, 18, 0, 0, 0, 0, time.UTC),
+			want:   "Today is Sweetmorn, the 42nd of Bureaucracy, 3161. ",
+		},
+		{
+			name:   "St Tibs Day",
+			format: "Today is %{%A, the %e of %B%}, %Y. %N%nCelebrate %H!",
+			date:   time.Date(1996, time.February, 29, 0, 0, 0, 0, time.UTC),
+			want:   "Today is St. Tib's Day, 3162. ",
+		},
+		{
+			name:   "St Tibs Day Without Block",
+			format: "%A (%a), %B (%b) %d%e, %Y YOLD",
+			date:   time.Date(1996, time.February, 29, 0, 0, 0, 0, time.UTC),
+			want:   "St. Tib's Day (Tib), Chaos (Chs) , 3162 YOLD",
+		},
+		{
+			name:   "St Tibs Day Strict Without Block",
+			format: "%Y %d",
+			date:   time.Date(1996, time.February, 29, 0, 0, 0, 0, time.UTC),
+			opts:   []Option{WithStrictTibsDay(true)},
+			err:    true,
+		},
+		{
+			name:   "St Tibs Day Strict With Block",
+			format: "%{%A, %B %d%}, %Y YOLD",
+			date:   time.Date(1996, time.February, 29, 0, 0, 0, 0, time.UTC),
+			opts:   []Option{WithStrictTibsDay(true)},
+			want:   "St. Tib's Day, 3162 YOLD",
+		},
+		{
+			name:   "Not St Tibs Day Strict Without Block",
+			format: "%A, %B %d, %Y YOLD",
+			date:   time.Date(1996, time.March, 1, 0, 0, 0, 0, time.UTC),
+			opts:   []Option{WithStrictTibsDay(true)},
+			want:   "Setting Orange, Chaos 60, 3162 YOLD",
+		},
+		{
+			name:   "German Locale",
+			format: "%{%A (%a), %e %B (%b)%}, %y %H",
+			date:   time.Date(1995, time.September, 26, 0, 0, 0, 0, time.UTC),
+			opts:   []Option{WithLocale(German)},
+			want:   "Prickel-Prickel (PP), 50. Bürokratie (Brk), 3161. Büroflux",
+		},
+		{
+			name:   "French Locale St Tibs Day",
+			format: "%{%A, %e %B%}, %Y",
+			date:   time.Date(1996, time.February, 29, 0, 0, 0, 0, time.UTC),
+			opts:   []Option{WithLocale(French)},
+			want:   "Saint-Tib, 3162",
+		},
+		{
+			name:   "Whitespace And Percent",
+			format: "%%%t%n",
+			date:   time.Date(1995, time.September, 26, 0, 0, 0, 0, time.UTC),
+			want:   "%\t\n",
+		},
+		{
+			name:   "Days Until X-Day",
+			format: "%X",
+			date:   time.Date(8661, time.July, 1, 12, 0, 0, 0, time.UTC),
+			want:   "4",
+		},
+		{
+			name:   "Days Since X-Day",
+			format: "%X",
+			date:   time.Date(8661, time.July, 15, 0, 0, 0, 0, time.UTC),
+			want:   "-10",
+		},
+		{
+			name:   "Days Until Custom X-Day",
+			format: "%X",
+			date:   time.Date(1998, time.July, 4, 23, 0, 0, 0, time.UTC),
+			opts:   []Option{WithXDay(time.Date(1998, time.July, 5, 7, 0, 0, 0, time.UTC))},
+			want:   "1",
+		},
+		{
+			name:   "Days Since Custom X-Day",
+			format: "%X",
+			date:   time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC),
+			opts:   []Option{WithXDay(time.Date(1998, time.July, 5, 0, 0, 0, 0, time.UTC))},
+			want:   "-10332",
+		},
+		{
+			name:   "Gregorian Date And Time",
+			format: "%A, %B %d %G(%Y-%m-%d %H:%M)",
+			date:   time.Date(1995, time.September, 26, 21, 5, 0, 0, time.UTC),
+			want:   "Prickle-Prickle, Bureaucracy 50 1995-09-26 21:05",
+		},
+		{
+			name:   "Gregorian Names And Numbers",
+			format: "%G(%a %A %b %B %C %e %I %j %k %l %p %u %w %y %z %Z %s %%)",
+			date:   time.Date(1995, time.September, 3, 21, 5, 0, 0, time.FixedZone("EST", -5*60*60)),
+			want:   "Sun Sunday Sep September 19  3 09 246 21  9 PM 7 0 95 -0500 EST 810180300 %",
+		},
+		{
+			name:   "Gregorian Date On St Tibs Day",
+			format: "%{%A%} %G(%D %T%n%F%t%R)",
+			date:   time.Date(1996, time.February, 29, 23, 59, 58, 0, time.UTC),
+			want:   "St. Tib's Day 02/29/96 23:59:58\n1996-02-29\t23:59",
+		},
+		{
+			name:   "No Padding",
+			format: "%-d %-5d",
+			date:   time.Date(1995, time.January, 5, 0, 0, 0, 0, time.UTC),
+			want:   "5 5",
+		},
+		{
+			name:   "Zero Padding",
+			format: "%03d %2d %06Y",
+			date:   time.Date(1995, time.January, 5, 0, 0, 0, 0, time.UTC),
+			want:   "005 05 003161",
+		},
+		{
+			name:   "Space Padding",
+			format: "[%_3d] [%8e] [%12B]",
+			date:   time.Date(1995, time.January, 5, 0, 0, 0, 0, time.UTC),
+			want:   "[  5] [     5th] [       Chaos]",
+		},
+		{
+			name:   "Width Counts Characters",
+			format: "[%12B]",
+			date:   time.Date(1995, time.September, 26, 0, 0, 0, 0, time.UTC),
+			opts:   []Option{WithLocale(German)},
+			want:   "[  Bürokratie]",
+		},
+		{
+			name:   "Upper Case",
+			format: "%^A, %^10b",
+			date:   time.Date(1995, time.September, 26, 0, 0, 0, 0, time.UTC),
+			want:   "PRICKLE-PRICKLE,        BCY",
+		},
+		{
+			name:   "Opposite Case",
+			format: "%#B %#a %#H",
+			date:   time.Date(1995, time.September, 26, 0, 0, 0, 0, time.UTC),
+			want:   "BUREAUCRACY pp BUREFLUX",
+		},
+		{
+			name:   "Zero Padding Negative Number",
+			format: "%05X",
+			date:   time.Date(1998, time.July, 9, 0, 0, 0, 0, time.UTC),
+			opts:   []Option{WithXDay(time.Date(1998, time.July, 5, 0, 0, 0, 0, time.UTC))},
+			want:   "-0004",
+		},
+		{
+			name:   "Modifiers On St Tibs Day",
+			format: "%^{%A%} %-5d|",
+			date:   time.Date(1996, time.February, 29, 0, 0, 0, 0, time.UTC),
+			want:   "ST. TIB'S DAY |",
+		},
+		{
+			name:   "Widths Of Empty Directives On St Tibs Day",
+			format: "[%03d] [%_3d] [%5e] [%10H]",
+			date:   time.Date(2000, time.February, 29, 0, 0, 0, 0, time.UTC),
+			want:   "[] [] [] []",
+		},
+		{
+			name:   "Gregorian Directive With Modifiers",
+			format: "%^G(%a %b)",
+			date:   time.Date(1995, time.September, 26, 0, 0, 0, 0, time.UTC),
+			want:   "TUE SEP",
+		},
+		{
+			name:   "Unknown Directive",
+			format: "%q",
+			date:   time.Date(1995, time.September, 26, 0, 0, 0, 0, time.UTC),
+			err:    true,
+		},
+		{
+			name:   "Incomplete Directive",
+			format: "100%",
+			date:   time.Date(1995, time.September, 26, 0, 0, 0, 0, time.UTC),
+			err:    true,
+		},
+	}
+
+	for _, test := range tests {
+		// shadow loop var to prevent nasty bugs
+		test := test
+
+		// trim whitespace from name of test case
+		name := strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+
+			return r
+		}, test.name)
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act
+			have, err := Format(test.format, test.date, test.opts...)
+
+			// Assert
+			if test.err {
+				if err == nil {
+					t.Fatalf("error: have nil, want error")
+				}
+
+				return // don't keep testing, expected failure detected
+			}
+
+			if err != nil {
+				t.Fatalf("error: have %q, want nil", err)
+			}
+
+			if want := test.want; have != want {
+				t.Errorf("format: have %q, want %q", have, want)
+			}
+		})
+	}
+}
+
+func TestFormatMagic(t *testing.T) {
+	t.Parallel()
+
+	// Act
+	have, err := Format("%.", time.Now())
+
+	// Assert
+	if err != nil {
+		t.Fatalf("error: have %q, want nil", err)
+	}
+
+	for _, want := range DefaultQuotes {
+		if have == want {
+			return
+		}
+	}
+
+	t.Errorf("magic: have %q, want one of %q", have, DefaultQuotes)
+}
@@ -0,0 +1,377 @@
+package format
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Token is a single element of a compiled layout, it is either a run of literal
+// text or a single directive.
+type Token struct {
+	Offset    int       // byte offset of the token within the layout
+	Literal   string    // literal text, empty if the token is a directive
+	Directive Directive // directive, empty if the token is literal text
+	Argument  string    // argument of the directive, i.e. the layout of %G
+	Flags     string    // flags of the directive, i.e. "0" of %03d
+	Width     int       // minimum width of the directive, i.e. 3 of %03d
+}
+
+// Layout is a compiled format string, it can be used to format many dates
+// without scanning the format string each time.
+type Layout struct {
+	source string                          // original format string
+	tokens []Token                         // parsed elements of the format string
+	custom map[Directive]func(Date) string // user-defined directives of the layout
+}
+
+// SyntaxError describes a problem with a format string.
+type SyntaxError struct {
+	Layout string // format string being parsed
+	Offset int    // byte offset of the problem within the layout
+	Msg    string // description of the problem
+}
+
+// Error implements the error interface.
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("%s at offset %d", e.Msg, e.Offset)
+}
+
+// ErrTibsDay is returned in strict mode when a directive that has no meaning on
+// St. Tib's Day is used outside of a %{ %} block on St. Tib's Day.
+var ErrTibsDay = errors.New("directive undefined on St. Tib's Day")
+
+// directives is the set of directives understood by the parser, which are
+// the directives of the registry, including the user-defined directives.
+var directives = map[Directive]bool{}
+
+func init() {
+	for _, info := range registry {
+		directives[info.Directive] = true
+	}
+}
+
+// numericDirectives are the directives which format a number, and which are
+// padded with zeros instead of spaces unless a flag says otherwise.
+var numericDirectives = map[Directive]bool{
+	OrdinalDayDirective:  true,
+	OrdinalYearDirective: true,
+	XDayDirective:        true,
+}
+
+const (
+	// directiveFlags are the GNU flags which may follow the percent sign of a
+	// directive: - does not pad, _ pads with spaces, 0 pads with zeros, ^
+	// converts to upper case, and # converts to the opposite case.
+	directiveFlags = "-_0^#"
+
+	// maxWidth is the largest width of a directive.
+	maxWidth = 1024
+)
+
+// tibsDayUndefined is the set of directives which have no meaning on St. Tib's
+// Day, unless they are enclosed in a %{ %} block.
+var tibsDayUndefined = map[Directive]bool{
+	FullWeekdayDirective: true,
+	AbbrWeekdayDirective: true,
+	FullSeasonDirective:  true,
+	AbbrSeasonDirective:  true,
+	OrdinalDayDirective:  true,
+	CardinalDayDirective: true,
+}
+
+// Parse compiles a format string into a layout.
+//
+// A *SyntaxError is returned if the format string contains an unknown
+// directive, ends with a lone percent sign, contains unbalanced or nested %{
+// and %} directives, or contains a %G directive without a valid strftime(3)
+// layout in parentheses. User-defined directives are those registered when the
+// layout is parsed.
+//
+// Every directive may have GNU flags and a width between the percent sign and
+// the directive, in that order (i.e. %-d, %03d, %^A, %#B, or %10A). A width
+// above 1024 is a *SyntaxError as well.
+func Parse(layout string) (*Layout, error) {
+	l := &Layout{source: layout}
+
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	// block is the offset of the open %{ directive, or -1 if there is none.
+	block := -1
+
+	for i := 0; i < len(layout); {
+		if layout[i] != '%' {
+			end := strings.IndexByte(layout[i:], '%')
+			if end < 0 {
+				end = len(layout) - i
+			}
+
+			l.tokens = append(l.tokens, Token{Offset: i, Literal: layout[i : i+end]})
+			i += end
+
+			continue
+		}
+
+		// skip the flags and the width, to the character of the directive
+		j := i + 1
+		for j < len(layout) && strings.IndexByte(directiveFlags, layout[j]) >= 0 {
+			j++
+		}
+
+		k := j
+		for k < len(layout) && '0' <= layout[k] && layout[k] <= '9' {
+			k++
+		}
+
+		width := 0
+
+		if k > j {
+			var err error
+
+			if width, err = strconv.Atoi(layout[j:k]); err != nil || width > maxWidth {
+				return nil, &SyntaxError{Layout: layout, Offset: j, Msg: fmt.Sprintf("invalid width %q, at most %d", layout[j:k], maxWidth)}
+			}
+		}
+
+		if k >= len(layout) {
+			return nil, &SyntaxError{Layout: layout, Offset: i, Msg: "incomplete directive"}
+		}
+
+		directive := Directive("%" + layout[k:k+1])
+		token := Token{Offset: i, Directive: directive, Flags: layout[i+1 : j], Width: width}
+
+		switch {
+		case !directives[directive]:
+			return nil, &SyntaxError{Layout: layout, Offset: i, Msg: fmt.Sprintf("unknown directive %q", directive)}
+		case directive == StartTibsDayDirective && block >= 0:
+			return nil, &SyntaxError{Layout: layout, Offset: i, Msg: fmt.Sprintf("nested %s", directive)}
+		case directive == StartTibsDayDirective:
+			block = i
+		case directive == EndTibsDayDirective && block < 0:
+			return nil, &SyntaxError{Layout: layout, Offset: i, Msg: fmt.Sprintf("unexpected %s", directive)}
+		case directive == EndTibsDayDirective:
+			block = -1
+		case directive == GregorianDirective:
+			end, err := parseStrftime(layout, i, k+1)
+			if err != nil {
+				return nil, err
+			}
+
+			// the argument is the layout between the parentheses
+			token.Argument = layout[k+2 : end]
+			l.tokens = append(l.tokens, token)
+			i = end + 1
+
+			continue
+		case custom[directive] != nil:
+			if l.custom == nil {
+				l.custom = map[Directive]func(Date) string{}
+			}
+
+			l.custom[directive] = custom[directive]
+		}
+
+		l.tokens = append(l.tokens, token)
+		i = k + 1
+	}
+
+	if block >= 0 {
+		return nil, &SyntaxError{Layout: layout, Offset: block, Msg: fmt.Sprintf("unclosed %s", StartTibsDayDirective)}
+	}
+
+	return l, nil
+}
+
+// MustParse is like Parse but panics if the format string cannot be parsed. It
+// simplifies safe initialisation of global variables holding layouts.
+func MustParse(layout string) *Layout {
+	l, err := Parse(layout)
+	if err != nil {
+		panic(`format: Parse(` + strconv.Quote(layout) + `): ` + err.Error())
+	}
+
+	return l
+}
+
+// String returns the format string the layout was compiled from.
+func (l *Layout) String() string {
+	return l.source
+}
+
+// Tokens returns the elements of the layout, in order.
+func (l *Layout) Tokens() []Token {
+	return append([]Token(nil), l.tokens...)
+}
+
+// Format returns a textual representation of the Discordian date of the given
+// time, formatted according to the layout.
+//
+// St. Tib's Day is outside of the regular weeks and seasons, so when it is
+// formatted without a %{ %} block, the weekday directives %A and %a render the
+// full and abbreviated name of St. Tib's Day, the season directives %B and %b
+// render the season it falls in, Chaos, and the day directives %d and %e render
+// nothing. With WithStrictTibsDay, an error wrapping ErrTibsDay is returned
+// instead.
+//
+// The flags and the width of a directive apply to its text. A width pads the
+// text on the left, with zeros for the numbers of %d, %Y, and %X, and with
+// spaces otherwise. A directive which formats nothing is not padded.
+func (l *Layout) Format(t time.Time, opts ...Option) (string, error) {
+	var out bytes.Buffer
+
+	d, o := NewDate(t), newOptions(opts)
+
+	// skipping is set while inside a %{ %} block on St. Tib's Day.
+	skipping := false
+
+	for _, token := range l.tokens {
+		if skipping {
+			skipping = token.Directive != EndTibsDayDirective
+			continue
+		}
+
+		if d.TibsDay && o.strict && tibsDayUndefined[token.Directive] {
+			return "", fmt.Errorf("%w: %s at offset %d", ErrTibsDay, token.Directive, token.Offset)
+		}
+
+		// start is where the text of the directive begins
+		start := out.Len()
+
+		switch token.Directive {
+		case "":
+			out.WriteString(token.Literal)
+		case FullWeekdayDirective:
+			if d.TibsDay {
+				out.WriteString(o.locale.TibsDay)
+			} else {
+				out.WriteString(o.locale.Weekday(d.Weekday))
+			}
+		case AbbrWeekdayDirective:
+			if d.TibsDay {
+				out.WriteString(o.locale.TibsDayAbbr)
+			} else {
+				out.WriteString(o.locale.WeekdayAbbr(d.Weekday))
+			}
+		case FullSeasonDirective:
+			out.WriteString(o.locale.Season(d.Season))
+		case AbbrSeasonDirective:
+			out.WriteString(o.locale.SeasonAbbr(d.Season))
+		case OrdinalDayDirective:
+			if !d.TibsDay {
+				out.WriteString(strconv.Itoa(d.Day))
+			}
+		case CardinalDayDirective:
+			if !d.TibsDay {
+				out.WriteString(o.locale.Ordinal(d.Day))
+			}
+		case OrdinalYearDirective:
+			out.WriteString(strconv.Itoa(d.YOLD))
+		case CardinalYearDirective:
+			out.WriteString(o.locale.Ordinal(d.YOLD))
+		case HolydayDirective:
+			if holyday, ok := d.Holyday(); ok {
+				out.WriteString(o.locale.Holyday(holyday))
+			}
+		case NonHolidayDirective:
+			if _, ok := d.Holyday(); !ok {
+				return out.String(), nil
+			}
+		case NewlineDirective:
+			out.WriteByte('\n')
+		case TabDirective:
+			out.WriteByte('\t')
+		case PercentDirective:
+			out.WriteByte('%')
+		case XDayDirective:
+			out.WriteString(strconv.Itoa(DaysUntil(t, o.xDay)))
+		case StartTibsDayDirective:
+			if d.TibsDay {
+				out.WriteString(o.locale.TibsDay)
+				skipping = true
+			}
+		case EndTibsDayDirective:
+			// nothing to do, the block was not skipped
+		case MagicDirective:
+			out.WriteString(o.quotes.Select(d, o.selection))
+		case GregorianDirective:
+			out.WriteString(strftime(t, token.Argument))
+		default:
+			out.WriteString(l.custom[token.Directive](d))
+		}
+
+		if token.Flags != "" || token.Width > 0 {
+			text := modify(token, out.String()[start:])
+
+			out.Truncate(start)
+			out.WriteString(text)
+		}
+	}
+
+	return out.String(), nil
+}
+
+// modify applies the flags and the width of the directive to its text.
+func modify(token Token, text string) string {
+	pad, upper, opposite := byte(' '), false, false
+
+	// a directive which formats nothing, i.e. %d on St. Tib's Day, stays empty
+	if text == "" {
+		return text
+	}
+
+	if numericDirectives[token.Directive] {
+		pad = '0'
+	}
+
+	for i := 0; i < len(token.Flags); i++ {
+		switch token.Flags[i] {
+		case '-':
+			pad = 0
+		case '_':
+			pad = ' '
+		case '0':
+			pad = '0'
+		case '^':
+			upper = true
+		case '#':
+			opposite = true
+		}
+	}
+
+	switch {
+	case upper:
+		text = strings.ToUpper(text)
+	case opposite:
+		text = oppositeCase(text)
+	}
+
+	n := token.Width - utf8.RuneCountInString(text)
+	if pad == 0 || n <= 0 {
+		return text
+	}
+
+	padding := strings.Repeat(string(pad), n)
+
+	// zeros go between the sign and the digits of a negative number
+	if pad == '0' && strings.HasPrefix(text, "-") {
+		return "-" + padding + text[1:]
+	}
+
+	return padding + text
+}
+
+// oppositeCase returns the text in upper case, or in lower case if it already
+// is in upper case, as GNU date does for the # flag (i.e. CHAOS for Chaos, and
+// pp for PP).
+func oppositeCase(text string) string {
+	if upper := strings.ToUpper(text); upper != text {
+		return upper
+	}
+
+	return strings.ToLower(text)
+}
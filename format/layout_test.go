@@ -0,0 +1,224 @@
+package format
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string  // name of the test case
+		layout string  // input format string
+		want   []Token // expected tokens
+		offset int     // expected error offset (if want is nil expect err)
+	}{
+		{
+			name:   "Empty Layout",
+			layout: "",
+			want:   []Token{},
+		},
+		{
+			name:   "Only Literal",
+			layout: "Hail Eris",
+			want:   []Token{{Offset: 0, Literal: "Hail Eris"}},
+		},
+		{
+			name:   "Only Directive",
+			layout: "%A",
+			want:   []Token{{Offset: 0, Directive: FullWeekdayDirective}},
+		},
+		{
+			name:   "Mixed Literals And Directives",
+			layout: "%{%A, %B %d%}, %Y YOLD",
+			want: []Token{
+				{Offset: 0, Directive: StartTibsDayDirective},
+				{Offset: 2, Directive: FullWeekdayDirective},
+				{Offset: 4, Literal: ", "},
+				{Offset: 6, Directive: FullSeasonDirective},
+				{Offset: 8, Literal: " "},
+				{Offset: 9, Directive: OrdinalDayDirective},
+				{Offset: 11, Directive: EndTibsDayDirective},
+				{Offset: 13, Literal: ", "},
+				{Offset: 15, Directive: OrdinalYearDirective},
+				{Offset: 17, Literal: " YOLD"},
+			},
+		},
+		{
+			name:   "Escaped Percent Before Brace",
+			layout: "%%}",
+			want: []Token{
+				{Offset: 0, Directive: PercentDirective},
+				{Offset: 2, Literal: "}"},
+			},
+		},
+		{
+			name:   "Unknown Directive",
+			layout: "Today is %q",
+			offset: 9,
+		},
+		{
+			name:   "Incomplete Directive",
+			layout: "100%",
+			offset: 3,
+		},
+		{
+			name:   "Unclosed Block",
+			layout: "%A %{%B",
+			offset: 3,
+		},
+		{
+			name:   "Unopened Block",
+			layout: "%A %B%}",
+			offset: 5,
+		},
+		{
+			name:   "Nested Block",
+			layout: "%{%A %{%B%}%}",
+			offset: 5,
+		},
+		{
+			name:   "Gregorian Directive",
+			layout: "%A %G(%Y-%m-%d (%H%))!",
+			want: []Token{
+				{Offset: 0, Directive: FullWeekdayDirective},
+				{Offset: 2, Literal: " "},
+				{Offset: 3, Directive: GregorianDirective, Argument: "%Y-%m-%d (%H%)"},
+				{Offset: 21, Literal: "!"},
+			},
+		},
+		{
+			name:   "Gregorian Directive Without Layout",
+			layout: "%A %G",
+			offset: 3,
+		},
+		{
+			name:   "Unclosed Gregorian Directive",
+			layout: "%A %G(%Y",
+			offset: 3,
+		},
+		{
+			name:   "Unknown Gregorian Directive",
+			layout: "%A %G(%Y %Q)",
+			offset: 9,
+		},
+		{
+			name:   "Incomplete Gregorian Directive",
+			layout: "%G(%",
+			offset: 3,
+		},
+		{
+			name:   "Flags And Width",
+			layout: "%-d %03d %^A %10A",
+			want: []Token{
+				{Offset: 0, Directive: OrdinalDayDirective, Flags: "-"},
+				{Offset: 3, Literal: " "},
+				{Offset: 4, Directive: OrdinalDayDirective, Flags: "0", Width: 3},
+				{Offset: 8, Literal: " "},
+				{Offset: 9, Directive: FullWeekdayDirective, Flags: "^"},
+				{Offset: 12, Literal: " "},
+				{Offset: 13, Directive: FullWeekdayDirective, Width: 10},
+			},
+		},
+		{
+			name:   "Gregorian Directive With Flags",
+			layout: "%^20G(%b)",
+			want:   []Token{{Offset: 0, Directive: GregorianDirective, Argument: "%b", Flags: "^", Width: 20}},
+		},
+		{
+			name:   "Incomplete Flags",
+			layout: "%A %-",
+			offset: 3,
+		},
+		{
+			name:   "Unknown Directive With Width",
+			layout: "%A %5(",
+			offset: 3,
+		},
+		{
+			name:   "Width Too Large",
+			layout: "%A %_2000A",
+			offset: 5,
+		},
+	}
+
+	for _, test := range tests {
+		// shadow loop var to prevent nasty bugs
+		test := test
+
+		// trim whitespace from name of test case
+		name := strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+
+			return r
+		}, test.name)
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act
+			layout, err := Parse(test.layout)
+
+			// Assert
+			if test.want == nil {
+				var syntaxErr *SyntaxError
+
+				if !errors.As(err, &syntaxErr) {
+					t.Fatalf("error: have %v, want *SyntaxError", err)
+				}
+
+				if have, want := syntaxErr.Offset, test.offset; have != want {
+					t.Errorf("offset: have %d, want %d", have, want)
+				}
+
+				return // don't keep testing, expected failure detected
+			}
+
+			if err != nil {
+				t.Fatalf("error: have %q, want nil", err)
+			}
+
+			if have, want := layout.Tokens(), test.want; len(have) != len(want) || len(have) > 0 && !reflect.DeepEqual(have, want) {
+				t.Errorf("tokens: have %+v, want %+v", have, want)
+			}
+
+			if have, want := layout.String(), test.layout; have != want {
+				t.Errorf("string: have %q, want %q", have, want)
+			}
+		})
+	}
+}
+
+func TestLayoutReuse(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	layout := MustParse("%{%A, %B %d%}, %Y YOLD")
+
+	tests := map[time.Time]string{
+		time.Date(1995, time.September, 26, 0, 0, 0, 0, time.UTC): "Prickle-Prickle, Bureaucracy 50, 3161 YOLD",
+		time.Date(1996, time.February, 29, 0, 0, 0, 0, time.UTC):  "St. Tib's Day, 3162 YOLD",
+		time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC):    "Sweetmorn, Chaos 1, 3166 YOLD",
+	}
+
+	for date, want := range tests {
+		// Act
+		have, err := layout.Format(date)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("error: have %q, want nil", err)
+		}
+
+		if have != want {
+			t.Errorf("format: have %q, want %q", have, want)
+		}
+	}
+}
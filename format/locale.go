@@ -0,0 +1,249 @@
+package format
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Locale holds the names and ordinal rules of a language, used by the
+// directives to format a date in that language.
+type Locale struct {
+	Name         string     `json:"name"`          // language code (i.e. en)
+	Weekdays     [5]string  `json:"weekdays"`      // full names of the weekdays
+	WeekdayAbbrs [5]string  `json:"weekday_abbrs"` // abbreviated names of the weekdays
+	Seasons      [5]string  `json:"seasons"`       // full names of the seasons
+	SeasonAbbrs  [5]string  `json:"season_abbrs"`  // abbreviated names of the seasons
+	Holydays     [10]string `json:"holydays"`      // names of the holydays
+	TibsDay      string     `json:"tibs_day"`      // full name of St. Tib's Day
+	TibsDayAbbr  string     `json:"tibs_day_abbr"` // abbreviated name of St. Tib's Day
+	Ordinals     Ordinals   `json:"ordinals"`      // rules for ordinal suffixes
+}
+
+// Ordinals are the rules to add an ordinal suffix to a number. The suffix is
+// looked up by the whole number first, then by its last two digits, then by
+// its last digit, and if none of these match the default suffix is used.
+type Ordinals struct {
+	Exact   map[int]string `json:"exact,omitempty"`    // suffixes by whole number
+	LastTwo map[int]string `json:"last_two,omitempty"` // suffixes by the last two digits
+	Last    map[int]string `json:"last,omitempty"`     // suffixes by the last digit
+	Default string         `json:"default"`            // suffix of all other numbers
+}
+
+// Format formats n with its ordinal suffix (i.e. 23rd).
+func (o Ordinals) Format(n int) string {
+	abs := n
+	if abs < 0 {
+		abs = -abs
+	}
+
+	suffix, ok := o.Exact[n]
+
+	if !ok {
+		suffix, ok = o.LastTwo[abs%100]
+	}
+
+	if !ok {
+		suffix, ok = o.Last[abs%10]
+	}
+
+	if !ok {
+		suffix = o.Default
+	}
+
+	return strconv.Itoa(n) + suffix
+}
+
+// English is the default locale.
+var English = &Locale{
+	Name:         "en",
+	Weekdays:     [5]string{"Sweetmorn", "Boomtime", "Pungenday", "Prickle-Prickle", "Setting Orange"},
+	WeekdayAbbrs: [5]string{"SM", "BT", "PD", "PP", "SO"},
+	Seasons:      [5]string{"Chaos", "Discord", "Confusion", "Bureaucracy", "The Aftermath"},
+	SeasonAbbrs:  [5]string{"Chs", "Dsc", "Cfn", "Bcy", "Afm"},
+	Holydays: [10]string{
+		"Mungday", "Mojoday", "Syaday", "Zaraday", "Maladay",
+		"Chaoflux", "Discoflux", "Confuflux", "Bureflux", "Afflux",
+	},
+	TibsDay:     "St. Tib's Day",
+	TibsDayAbbr: "Tib",
+	Ordinals: Ordinals{
+		LastTwo: map[int]string{11: "th", 12: "th", 13: "th"},
+		Last:    map[int]string{1: "st", 2: "nd", 3: "rd"},
+		Default: "th",
+	},
+}
+
+// German is the German locale.
+var German = &Locale{
+	Name:         "de",
+	Weekdays:     [5]string{"Süßmorgen", "Boomtag", "Stechtag", "Prickel-Prickel", "Orangeuntergang"},
+	WeekdayAbbrs: [5]string{"SM", "BT", "ST", "PP", "OU"},
+	Seasons:      [5]string{"Chaos", "Zwietracht", "Verwirrung", "Bürokratie", "Die Nachwirkung"},
+	SeasonAbbrs:  [5]string{"Chs", "Zwt", "Vrw", "Brk", "Nwk"},
+	Holydays: [10]string{
+		"Mungtag", "Mojotag", "Syatag", "Zaratag", "Malatag",
+		"Chaoflux", "Zwieflux", "Verwirflux", "Büroflux", "Nachflux",
+	},
+	TibsDay:     "St. Tibs Tag",
+	TibsDayAbbr: "Tib",
+	Ordinals: Ordinals{
+		Default: ".",
+	},
+}
+
+// French is the French locale.
+var French = &Locale{
+	Name:         "fr",
+	Weekdays:     [5]string{"Doux-Matin", "Boumjour", "Piquanjour", "Pique-Pique", "Orange Couchant"},
+	WeekdayAbbrs: [5]string{"DM", "BJ", "PJ", "PP", "OC"},
+	Seasons:      [5]string{"Chaos", "Discorde", "Confusion", "Bureaucratie", "Les Séquelles"},
+	SeasonAbbrs:  [5]string{"Chs", "Dsc", "Cfs", "Bur", "Séq"},
+	Holydays: [10]string{
+		"Mungjour", "Mojojour", "Syajour", "Zarajour", "Malajour",
+		"Chaoflux", "Discoflux", "Confuflux", "Bureflux", "Séquflux",
+	},
+	TibsDay:     "Saint-Tib",
+	TibsDayAbbr: "Tib",
+	Ordinals: Ordinals{
+		Exact:   map[int]string{1: "er"},
+		Default: "e",
+	},
+}
+
+// Spanish is the Spanish locale.
+var Spanish = &Locale{
+	Name:         "es",
+	Weekdays:     [5]string{"Dulcemañana", "Estruendía", "Punzadía", "Pica-Pica", "Naranja Poniente"},
+	WeekdayAbbrs: [5]string{"DM", "ED", "PD", "PP", "NP"},
+	Seasons:      [5]string{"Caos", "Discordia", "Confusión", "Burocracia", "Las Secuelas"},
+	SeasonAbbrs:  [5]string{"Cas", "Dsc", "Cnf", "Bur", "Sec"},
+	Holydays: [10]string{
+		"Mungdía", "Mojodía", "Syadía", "Zaradía", "Maladía",
+		"Caoflux", "Discoflux", "Confuflux", "Buroflux", "Secuflux",
+	},
+	TibsDay:     "Día de San Tib",
+	TibsDayAbbr: "Tib",
+	Ordinals: Ordinals{
+		Default: ".º",
+	},
+}
+
+// locales are the built-in locales, by name.
+var locales = map[string]*Locale{
+	English.Name: English,
+	German.Name:  German,
+	French.Name:  French,
+	Spanish.Name: Spanish,
+}
+
+// LookupLocale returns the built-in locale for the given name, which may be a
+// language code (i.e. de) or a POSIX locale name (i.e. de_DE.UTF-8). The "C"
+// and "POSIX" locales are English.
+func LookupLocale(name string) (*Locale, bool) {
+	// Strip the territory, codeset, and modifier from the language code.
+	if i := strings.IndexAny(name, "_-.@"); i >= 0 {
+		name = name[:i]
+	}
+
+	if name == "C" || name == "POSIX" {
+		return English, true
+	}
+
+	locale, ok := locales[strings.ToLower(name)]
+
+	return locale, ok
+}
+
+// Locales returns the names of the built-in locales, in alphabetical order.
+func Locales() []string {
+	names := make([]string, 0, len(locales))
+
+	for name := range locales {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// LoadLocale reads a user-supplied locale from a JSON document, with the same
+// fields as the Locale type. All of the names must be given, the ordinal rules
+// are optional and numbers are formatted without a suffix if they are omitted.
+func LoadLocale(r io.Reader) (*Locale, error) {
+	var locale Locale
+
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&locale); err != nil {
+		return nil, fmt.Errorf("invalid locale: %w", err)
+	}
+
+	names := []struct {
+		field  string
+		values []string
+	}{
+		{"name", []string{locale.Name}},
+		{"weekdays", locale.Weekdays[:]},
+		{"weekday_abbrs", locale.WeekdayAbbrs[:]},
+		{"seasons", locale.Seasons[:]},
+		{"season_abbrs", locale.SeasonAbbrs[:]},
+		{"holydays", locale.Holydays[:]},
+		{"tibs_day", []string{locale.TibsDay}},
+		{"tibs_day_abbr", []string{locale.TibsDayAbbr}},
+	}
+
+	for _, name := range names {
+		for _, value := range name.values {
+			if value == "" {
+				return nil, fmt.Errorf("invalid locale: missing %s", name.field)
+			}
+		}
+	}
+
+	return &locale, nil
+}
+
+// Weekday returns the full name of the weekday.
+func (l *Locale) Weekday(w Weekday) string {
+	return lookup(l.Weekdays[:], int(w))
+}
+
+// WeekdayAbbr returns the abbreviated name of the weekday.
+func (l *Locale) WeekdayAbbr(w Weekday) string {
+	return lookup(l.WeekdayAbbrs[:], int(w))
+}
+
+// Season returns the full name of the season.
+func (l *Locale) Season(s Season) string {
+	return lookup(l.Seasons[:], int(s))
+}
+
+// SeasonAbbr returns the abbreviated name of the season.
+func (l *Locale) SeasonAbbr(s Season) string {
+	return lookup(l.SeasonAbbrs[:], int(s))
+}
+
+// Holyday returns the name of the holyday.
+func (l *Locale) Holyday(h Holyday) string {
+	return lookup(l.Holydays[:], int(h))
+}
+
+// Ordinal formats n with its ordinal suffix.
+func (l *Locale) Ordinal(n int) string {
+	return l.Ordinals.Format(n)
+}
+
+// lookup returns the name at index i, or an empty string if i is out of range.
+func lookup(names []string, i int) string {
+	if i < 0 || i >= len(names) {
+		return ""
+	}
+
+	return names[i]
+}
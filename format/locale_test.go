@@ -0,0 +1,194 @@
+package format
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+)
+
+func TestOrdinals(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string   // name of the test case
+		locale *Locale  // locale of the ordinal rules
+		have   []int    // input numbers
+		want   []string // expected ordinals
+	}{
+		{
+			name:   "English",
+			locale: English,
+			have:   []int{1, 2, 3, 4, 11, 12, 13, 21, 22, 23, 73, 101, 111, 3161, -1, -12},
+			want:   []string{"1st", "2nd", "3rd", "4th", "11th", "12th", "13th", "21st", "22nd", "23rd", "73rd", "101st", "111th", "3161st", "-1st", "-12th"},
+		},
+		{
+			name:   "German",
+			locale: German,
+			have:   []int{1, 2, 23},
+			want:   []string{"1.", "2.", "23."},
+		},
+		{
+			name:   "French",
+			locale: French,
+			have:   []int{1, 2, 21},
+			want:   []string{"1er", "2e", "21e"},
+		},
+		{
+			name:   "Spanish",
+			locale: Spanish,
+			have:   []int{1, 2, 23},
+			want:   []string{"1.º", "2.º", "23.º"},
+		},
+	}
+
+	for _, test := range tests {
+		// shadow loop var to prevent nasty bugs
+		test := test
+
+		// trim whitespace from name of test case
+		name := strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+
+			return r
+		}, test.name)
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			for i, n := range test.have {
+				// Act
+				have := test.locale.Ordinal(n)
+
+				// Assert
+				if want := test.want[i]; have != want {
+					t.Errorf("ordinal %d: have %q, want %q", n, have, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLookupLocale(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]*Locale{
+		"en":          English,
+		"C":           English,
+		"POSIX":       English,
+		"de":          German,
+		"de_DE.UTF-8": German,
+		"de-AT":       German,
+		"fr_FR@euro":  French,
+		"ES":          Spanish,
+		"xx_XX":       nil,
+		"":            nil,
+	}
+
+	for name, want := range tests {
+		// Act
+		have, ok := LookupLocale(name)
+
+		// Assert
+		if ok != (want != nil) || have != want {
+			t.Errorf("locale %q: have %v (%t), want %v", name, have, ok, want)
+		}
+	}
+
+	if have, want := strings.Join(Locales(), ","), "de,en,es,fr"; have != want {
+		t.Errorf("locales: have %q, want %q", have, want)
+	}
+}
+
+func TestLoadLocale(t *testing.T) {
+	t.Parallel()
+
+	const complete = `{
+		"name": "la",
+		"weekdays": ["Dulcimane", "Boomdies", "Pungendies", "Pungi-Pungi", "Aurantium Occidens"],
+		"weekday_abbrs": ["DM", "BD", "PD", "PP", "AO"],
+		"seasons": ["Chaos", "Discordia", "Confusio", "Officium", "Consequentia"],
+		"season_abbrs": ["Chs", "Dsc", "Cfs", "Off", "Csq"],
+		"holydays": ["Mungdies", "Mojodies", "Syadies", "Zaradies", "Maladies", "Chaoflux", "Discoflux", "Confuflux", "Officiflux", "Consequiflux"],
+		"tibs_day": "Dies Sancti Tibi",
+		"tibs_day_abbr": "Tib",
+		"ordinals": {"default": "."}
+	}`
+
+	tests := []struct {
+		name string // name of the test case
+		have string // input JSON document
+		want string // expected formatted date (if empty expect err)
+	}{
+		{
+			name: "Complete Locale",
+			have: complete,
+			want: "Pungi-Pungi, Officium 50., 3161 Officiflux",
+		},
+		{
+			name: "Without Ordinals",
+			have: strings.Replace(complete, `"ordinals": {"default": "."}`, `"tibs_day": "Dies Sancti Tibi"`, 1),
+			want: "Pungi-Pungi, Officium 50, 3161 Officiflux",
+		},
+		{
+			name: "Missing Name",
+			have: strings.Replace(complete, `"Officium",`, `"",`, 1),
+		},
+		{
+			name: "Short List",
+			have: strings.Replace(complete, `"DM", "BD", `, ``, 1),
+		},
+		{
+			name: "Unknown Field",
+			have: strings.Replace(complete, `"name"`, `"language"`, 1),
+		},
+		{
+			name: "Not JSON",
+			have: "name = la",
+		},
+	}
+
+	for _, test := range tests {
+		// shadow loop var to prevent nasty bugs
+		test := test
+
+		// trim whitespace from name of test case
+		name := strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+
+			return r
+		}, test.name)
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act
+			locale, err := LoadLocale(strings.NewReader(test.have))
+
+			// Assert
+			if test.want == "" {
+				if err == nil {
+					t.Fatalf("error: have nil, want error")
+				}
+
+				return // don't keep testing, expected failure detected
+			}
+
+			if err != nil {
+				t.Fatalf("error: have %q, want nil", err)
+			}
+
+			date := time.Date(1995, time.September, 26, 0, 0, 0, 0, time.UTC)
+
+			if have, err := Format("%A, %B %e, %Y %H", date, WithLocale(locale)); err != nil {
+				t.Fatalf("format error: have %q, want nil", err)
+			} else if want := test.want; have != want {
+				t.Errorf("format: have %q, want %q", have, want)
+			}
+		})
+	}
+}
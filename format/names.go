@@ -0,0 +1,68 @@
+package format
+
+// Season is a season of the Discordian year.
+type Season int
+
+// The five seasons of the Discordian year, of 73 days each.
+const (
+	Chaos Season = iota
+	Discord
+	Confusion
+	Bureaucracy
+	TheAftermath
+)
+
+// String returns the full English name of the season (i.e. Chaos).
+func (s Season) String() string {
+	return English.Season(s)
+}
+
+// Abbr returns the abbreviated English name of the season (i.e. Chs).
+func (s Season) Abbr() string {
+	return English.SeasonAbbr(s)
+}
+
+// Weekday is a day of the Discordian week.
+type Weekday int
+
+// The five days of the Discordian week.
+const (
+	Sweetmorn Weekday = iota
+	Boomtime
+	Pungenday
+	PricklePrickle
+	SettingOrange
+)
+
+// String returns the full English name of the weekday (i.e. Sweetmorn).
+func (w Weekday) String() string {
+	return English.Weekday(w)
+}
+
+// Abbr returns the abbreviated English name of the weekday (i.e. SM).
+func (w Weekday) Abbr() string {
+	return English.WeekdayAbbr(w)
+}
+
+// Holyday is a Discordian holyday.
+type Holyday int
+
+// The apostle holydays, on the 5th day of each season, followed by the season
+// holydays, on the 50th day of each season.
+const (
+	Mungday Holyday = iota
+	Mojoday
+	Syaday
+	Zaraday
+	Maladay
+	Chaoflux
+	Discoflux
+	Confuflux
+	Bureflux
+	Afflux
+)
+
+// String returns the English name of the holyday (i.e. Confuflux).
+func (h Holyday) String() string {
+	return English.Holyday(h)
+}
@@ -0,0 +1,70 @@
+package format
+
+import "time"
+
+// Option configures how a layout is formatted.
+type Option func(*options)
+
+// options holds the settings used while formatting a layout.
+type options struct {
+	strict    bool           // return an error for directives undefined on St. Tib's Day
+	xDay      time.Time      // date counted down to by the %X directive
+	quotes    Quotes         // quotes formatted by the %. directive
+	selection QuoteSelection // how the quote is selected for the %. directive
+	locale    *Locale        // names and ordinal rules used by the directives
+}
+
+// newOptions applies the given options over the defaults.
+func newOptions(opts []Option) *options {
+	o := &options{xDay: XDay, quotes: DefaultQuotes, locale: English}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithStrictTibsDay sets whether formatting St. Tib's Day with a weekday,
+// season, or day directive outside of a %{ %} block is an error, rather than
+// being rendered according to the St. Tib's Day rules.
+func WithStrictTibsDay(strict bool) Option {
+	return func(o *options) {
+		o.strict = strict
+	}
+}
+
+// WithXDay sets the date of X-Day counted down to by the %X directive, only the
+// date is used and the time of day is ignored.
+//
+// Once the date being formatted is past X-Day, the count is negative and gives
+// the number of days since X-Day.
+func WithXDay(xDay time.Time) Option {
+	return func(o *options) {
+		o.xDay = xDay
+	}
+}
+
+// WithQuotes sets the quotes from which the magic directive %. selects one,
+// instead of the DefaultQuotes.
+func WithQuotes(quotes Quotes) Option {
+	return func(o *options) {
+		o.quotes = quotes
+	}
+}
+
+// WithQuoteSelection sets how the magic directive %. selects a quote, the
+// default is RandomQuote.
+func WithQuoteSelection(selection QuoteSelection) Option {
+	return func(o *options) {
+		o.selection = selection
+	}
+}
+
+// WithLocale sets the locale of the names and ordinal suffixes formatted by the
+// directives, the default is English.
+func WithLocale(locale *Locale) Option {
+	return func(o *options) {
+		o.locale = locale
+	}
+}
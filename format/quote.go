@@ -0,0 +1,125 @@
+package format
+
+import (
+	"bufio"
+	"hash/fnv"
+	"io"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Quotes is a collection of quotes, one of which is formatted by the magic
+// directive %. for every date.
+type Quotes []string
+
+// DefaultQuotes are the built-in quotes, taken from the Principia Discordia and
+// the exclamations of the original ddate.
+var DefaultQuotes = Quotes{
+	"Hail Eris!",
+	"All Hail Discordia!",
+	"Kallisti!",
+	"Fnord.",
+	"Or not.",
+	"Wibble.",
+	"Pzat!",
+	"P'tang!",
+	"Frink!",
+	"Slack!",
+	"Praise \"Bob\"!",
+	"Or kill me.",
+	"Grudnuk demand sustenance!",
+	"Keep the Lasagna flying!",
+	"You are what you see.",
+	"Or is it?",
+	"This statement is false.",
+	"Lies and slander, sire!",
+	"Hee hee hee!",
+	"Hail Eris, Hack Linux!",
+	"Five tons of flax!",
+	"We Discordians must stick apart.",
+	"Think for yourself, schmuck!",
+	"Convictions cause convicts.",
+	"It is my firm belief that it is a mistake to hold firm beliefs.",
+	"A Discordian is Prohibited of Believing what he reads.",
+	"There is no Goddess but Goddess and She is Your Goddess.",
+	"The Aneristic Principle is that of APPARENT ORDER; the Eristic Principle is that of APPARENT DISORDER.",
+	"If you can master nonsense as well as you have already learned to master sense, then each will expose the other for what it is: absurdity.",
+	"All statements are true in some sense, false in some sense, meaningless in some sense, true and false in some sense, true and meaningless in some sense, false and meaningless in some sense, and true and false and meaningless in some sense.",
+}
+
+// QuoteSelection specifies how a quote is selected for a date.
+type QuoteSelection int
+
+const (
+	// RandomQuote selects a different random quote every time.
+	RandomQuote QuoteSelection = iota
+
+	// DailyQuote selects the same quote for everyone on the same date.
+	DailyQuote
+)
+
+// ReadFortunes reads quotes in the format of fortune(6) files, in which the
+// quotes are separated by lines holding a single percent sign.
+//
+// Leading and trailing blank lines are removed from every quote, and empty
+// quotes are skipped.
+func ReadFortunes(r io.Reader) (Quotes, error) {
+	var quotes Quotes
+	var quote strings.Builder
+
+	flush := func() {
+		if text := strings.Trim(quote.String(), "\n"); strings.TrimSpace(text) != "" {
+			quotes = append(quotes, text)
+		}
+
+		quote.Reset()
+	}
+
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		if line := strings.TrimRight(scanner.Text(), "\r"); line == "%" {
+			flush()
+		} else {
+			quote.WriteString(line)
+			quote.WriteByte('\n')
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	flush()
+
+	return quotes, nil
+}
+
+// Select returns a quote for the given date, or an empty string if there are
+// no quotes.
+func (q Quotes) Select(d Date, selection QuoteSelection) string {
+	if len(q) == 0 {
+		return ""
+	}
+
+	if selection == DailyQuote {
+		hash := fnv.New32a()
+		hash.Write([]byte(d.String()))
+
+		return q[hash.Sum32()%uint32(len(q))]
+	}
+
+	randomMutex.Lock()
+	defer randomMutex.Unlock()
+
+	return q[random.Intn(len(q))]
+}
+
+// random is the source of chaos for randomly selected quotes.
+var random = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+// randomMutex locks access to the random source, which is not safe for
+// concurrent use on its own.
+var randomMutex sync.Mutex
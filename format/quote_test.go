@@ -0,0 +1,105 @@
+package format
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+)
+
+func TestReadFortunes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string // name of the test case
+		have string // input fortune file
+		want Quotes // expected quotes
+	}{
+		{
+			name: "Empty File",
+			have: "",
+		},
+		{
+			name: "Single Quote",
+			have: "Hail Eris!\n",
+			want: Quotes{"Hail Eris!"},
+		},
+		{
+			name: "Multiple Quotes",
+			have: "Hail Eris!\n%\nAll Hail Discordia!\n%\nKallisti!\n",
+			want: Quotes{"Hail Eris!", "All Hail Discordia!", "Kallisti!"},
+		},
+		{
+			name: "Multiline Quote",
+			have: "Convictions\ncause\nconvicts.\n%\n",
+			want: Quotes{"Convictions\ncause\nconvicts."},
+		},
+		{
+			name: "Blank Lines And Empty Quotes",
+			have: "%\n\nFnord.\n\n%\n   \n%\r\nOr not.\r\n",
+			want: Quotes{"Fnord.", "Or not."},
+		},
+		{
+			name: "Percent Sign Within Quote",
+			have: "100% chaos\n%\n",
+			want: Quotes{"100% chaos"},
+		},
+	}
+
+	for _, test := range tests {
+		// shadow loop var to prevent nasty bugs
+		test := test
+
+		// trim whitespace from name of test case
+		name := strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+
+			return r
+		}, test.name)
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act
+			quotes, err := ReadFortunes(strings.NewReader(test.have))
+
+			// Assert
+			if err != nil {
+				t.Fatalf("error: have %q, want nil", err)
+			}
+
+			if have, want := quotes, test.want; !reflect.DeepEqual(have, want) {
+				t.Errorf("quotes: have %q, want %q", have, want)
+			}
+		})
+	}
+}
+
+func TestQuotesSelect(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	quotes := Quotes{"Hail Eris!", "All Hail Discordia!", "Kallisti!", "Fnord.", "Or not."}
+	today := NewDate(time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC))
+
+	// Act
+	daily := quotes.Select(today, DailyQuote)
+
+	// Assert
+	for i := 0; i < 100; i++ {
+		if have, want := quotes.Select(today, DailyQuote), daily; have != want {
+			t.Fatalf("daily quote: have %q, want %q", have, want)
+		}
+
+		if have := quotes.Select(today, RandomQuote); !strings.Contains(strings.Join(quotes, "\n"), have) {
+			t.Fatalf("random quote: have %q, want one of %q", have, quotes)
+		}
+	}
+
+	if have, want := (Quotes{}).Select(today, RandomQuote), ""; have != want {
+		t.Errorf("empty quotes: have %q, want %q", have, want)
+	}
+}
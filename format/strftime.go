@@ -0,0 +1,89 @@
+package format
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// strftimeVerbs are the strftime(3) conversions understood inside a %G(...)
+// directive, by the letter following the percent sign. The names of months and
+// weekdays are always in English.
+var strftimeVerbs = map[byte]func(t time.Time) string{
+	'a': func(t time.Time) string { return t.Format("Mon") },
+	'A': func(t time.Time) string { return t.Format("Monday") },
+	'b': func(t time.Time) string { return t.Format("Jan") },
+	'B': func(t time.Time) string { return t.Format("January") },
+	'C': func(t time.Time) string { return fmt.Sprintf("%02d", t.Year()/100) },
+	'd': func(t time.Time) string { return t.Format("02") },
+	'D': func(t time.Time) string { return t.Format("01/02/06") },
+	'e': func(t time.Time) string { return t.Format("_2") },
+	'F': func(t time.Time) string { return t.Format("2006-01-02") },
+	'h': func(t time.Time) string { return t.Format("Jan") },
+	'H': func(t time.Time) string { return t.Format("15") },
+	'I': func(t time.Time) string { return t.Format("03") },
+	'j': func(t time.Time) string { return fmt.Sprintf("%03d", t.YearDay()) },
+	'k': func(t time.Time) string { return fmt.Sprintf("%2d", t.Hour()) },
+	'l': func(t time.Time) string { return fmt.Sprintf("%2d", (t.Hour()+11)%12+1) },
+	'm': func(t time.Time) string { return t.Format("01") },
+	'M': func(t time.Time) string { return t.Format("04") },
+	'n': func(t time.Time) string { return "\n" },
+	'p': func(t time.Time) string { return t.Format("PM") },
+	'R': func(t time.Time) string { return t.Format("15:04") },
+	's': func(t time.Time) string { return strconv.FormatInt(t.Unix(), 10) },
+	'S': func(t time.Time) string { return t.Format("05") },
+	't': func(t time.Time) string { return "\t" },
+	'T': func(t time.Time) string { return t.Format("15:04:05") },
+	'u': func(t time.Time) string { return strconv.Itoa((int(t.Weekday())+6)%7 + 1) },
+	'w': func(t time.Time) string { return strconv.Itoa(int(t.Weekday())) },
+	'y': func(t time.Time) string { return t.Format("06") },
+	'Y': func(t time.Time) string { return strconv.Itoa(t.Year()) },
+	'z': func(t time.Time) string { return t.Format("-0700") },
+	'Z': func(t time.Time) string { return t.Format("MST") },
+	'%': func(t time.Time) string { return "%" },
+	')': func(t time.Time) string { return ")" },
+}
+
+// parseStrftime returns the offset of the parenthesis closing the strftime(3)
+// layout of the %G directive at the given offset of the format string, which is
+// opened at start, after the flags and the width of the directive.
+func parseStrftime(layout string, offset, start int) (int, error) {
+	if start >= len(layout) || layout[start] != '(' {
+		return 0, &SyntaxError{Layout: layout, Offset: offset, Msg: fmt.Sprintf("missing ( after %s", GregorianDirective)}
+	}
+
+	for i := start + 1; i < len(layout); i++ {
+		switch {
+		case layout[i] == ')':
+			return i, nil
+		case layout[i] != '%':
+			continue
+		case i+1 >= len(layout):
+			return 0, &SyntaxError{Layout: layout, Offset: i, Msg: "incomplete Gregorian directive"}
+		case strftimeVerbs[layout[i+1]] == nil:
+			return 0, &SyntaxError{Layout: layout, Offset: i, Msg: fmt.Sprintf("unknown Gregorian directive %q", layout[i:i+2])}
+		default:
+			i++
+		}
+	}
+
+	return 0, &SyntaxError{Layout: layout, Offset: offset, Msg: fmt.Sprintf("unclosed %s(", GregorianDirective)}
+}
+
+// strftime returns the Gregorian date of t, in its own time zone, formatted
+// according to a strftime(3) layout accepted by parseStrftime.
+func strftime(t time.Time, layout string) string {
+	var out strings.Builder
+
+	for i := 0; i < len(layout); i++ {
+		if layout[i] == '%' {
+			out.WriteString(strftimeVerbs[layout[i+1]](t))
+			i++
+		} else {
+			out.WriteByte(layout[i])
+		}
+	}
+
+	return out.String()
+}
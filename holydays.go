@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/norwd/ddate/discordian"
+	"github.com/norwd/ddate/format"
+	"github.com/norwd/ddate/internal/os"
+)
+
+// holydaysFormat is used to print every holyday in a list of holydays.
+const holydaysFormat = "%{%B %d%}\t%H"
+
+// holydaysUsage describes the arguments of the holydays command.
+var holydaysUsage = usage{
+	args:    "[<YOLD>]",
+	summary: "List the holydays of the current YOLD, or of the given YOLD.",
+}
+
+// holydays prints the holydays of a YOLD, by default the current one, with
+// their Gregorian dates.
+func holydays(self string, args []string) {
+	var s settings
+
+	flags := newFlagSet(self, "holydays", holydaysUsage)
+	s.register(flags)
+
+	args, ok := parseFlags(self, flags, args)
+	if !ok {
+		return
+	}
+
+	if err := s.resolve(); err != nil {
+		errorf("%s: %s", self, err)
+		return
+	}
+
+	yold := format.NewDate(s.today).YOLD
+
+	switch len(args) {
+	case 0:
+		// list the holydays of the current YOLD
+	case 1:
+		var err error
+
+		if yold, err = strconv.Atoi(args[0]); err != nil {
+			errorf("%s: invalid YOLD %q", self, args[0])
+			return
+		}
+	default:
+		errorf("%s: too many arguments for YOLD", self)
+		return
+	}
+
+	if err := writeHolydays(os.Stdout, format.Holydays(yold), holydaysFormat, s.location, format.WithLocale(s.locale)); err != nil {
+		errorf("%s: %s", self, err)
+	}
+}
+
+// writeHolydays writes a table of the holydays, with their Gregorian dates in
+// the given time zone, and formatted in the layout.
+func writeHolydays(w io.Writer, holydays []format.Date, layout string, loc *time.Location, opts ...format.Option) error {
+	var table bytes.Buffer
+
+	out := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+
+	for _, holyday := range holydays {
+		date, err := holyday.Time(loc)
+		if err != nil {
+			return err
+		}
+
+		line, err := format.Format(layout, date, opts...)
+		if err != nil {
+			return err
+		}
+
+		// St. Tib's Day is not a holyday, so its name is an empty cell, which
+		// keeps the rows after it in the same columns
+		fmt.Fprintf(out, "%s\t%s\n", date.Format(discordian.GregorianFormat), line)
+	}
+
+	if err := out.Flush(); err != nil {
+		return err
+	}
+
+	// the empty cells are padded like any other, so trim the padding again
+	lines := strings.SplitAfter(table.String(), "\n")
+	for i, line := range lines {
+		if strings.HasSuffix(line, "\n") {
+			lines[i] = strings.TrimRight(line[:len(line)-1], " ") + "\n"
+		}
+	}
+
+	_, err := io.WriteString(w, strings.Join(lines, ""))
+
+	return err
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/norwd/ddate/format"
+	"github.com/norwd/ddate/internal/os"
+)
+
+const (
+	// icsDateFormat is the format of dates in iCalendar, as in RFC 5545.
+	icsDateFormat = "20060102"
+
+	// icsTimeFormat is the format of UTC date-times in iCalendar.
+	icsTimeFormat = "20060102T150405Z"
+
+	// icsLineLength is the maximum length of a line of iCalendar in octets,
+	// excluding the line break, after which the line is folded.
+	icsLineLength = 75
+
+	// maxYOLDs is the largest number of YOLDs exported at once.
+	maxYOLDs = 100
+
+	// icsFirstYOLD and icsLastYOLD are the YOLDs of the Gregorian years 1 and
+	// 9999, the only years with a date in iCalendar.
+	icsFirstYOLD, icsLastYOLD = 1 + 1166, 9999 + 1166
+)
+
+// icsEvent is an all-day event in an iCalendar file.
+type icsEvent struct {
+	uid     string    // unique identifier, stable across exports
+	summary string    // name of the event
+	date    time.Time // Gregorian date of the event
+}
+
+// icsUsage describes the arguments of the ics command.
+var icsUsage = usage{
+	args:    "[<YOLD> [<YOLD>]]",
+	summary: "Print an iCalendar file of the holydays of a range of YOLDs.",
+}
+
+// calendarFile prints an iCalendar file of the holydays of a range of YOLDs,
+// by default of the current YOLD, as all-day events. The YOLDs of the range
+// are given as the first and last YOLD.
+func calendarFile(self string, args []string) {
+	var s settings
+
+	flags := newFlagSet(self, "ics", icsUsage)
+	s.register(flags)
+
+	seasons := flags.Bool("seasons", false, "add an event on the first day of every season")
+
+	args, ok := parseFlags(self, flags, args)
+	if !ok {
+		return
+	}
+
+	if err := s.resolve(); err != nil {
+		errorf("%s: %s", self, err)
+		return
+	}
+
+	first := format.NewDate(s.today).YOLD
+	last := first
+
+	switch len(args) {
+	case 0:
+		// export the holydays of the current YOLD
+	case 1, 2:
+		var err error
+
+		if first, err = strconv.Atoi(args[0]); err != nil {
+			errorf("%s: invalid YOLD %q", self, args[0])
+			return
+		}
+
+		last = first
+
+		if len(args) == 2 {
+			if last, err = strconv.Atoi(args[1]); err != nil {
+				errorf("%s: invalid YOLD %q", self, args[1])
+				return
+			}
+		}
+	default:
+		errorf("%s: too many arguments for YOLDs", self)
+		return
+	}
+
+	if err := checkYOLDs(first, last); err != nil {
+		errorf("%s: %s", self, err)
+		return
+	}
+
+	if err := writeCalendar(os.Stdout, holydayEvents(first, last, *seasons, s.locale), s.today); err != nil {
+		errorf("%s: %s", self, err)
+	}
+}
+
+// checkYOLDs returns an error unless the range of YOLDs from first to last is
+// in order, spans at most maxYOLDs, and has dates in iCalendar.
+func checkYOLDs(first, last int) error {
+	if last < first {
+		return fmt.Errorf("last YOLD %d is before first YOLD %d", last, first)
+	} else if span := last - first + 1; span > maxYOLDs || span <= 0 {
+		return fmt.Errorf("too many YOLDs from %d to %d, at most %d", first, last, maxYOLDs)
+	} else if first < icsFirstYOLD || last > icsLastYOLD {
+		return fmt.Errorf("YOLDs from %d to %d out of range, want %d to %d", first, last, icsFirstYOLD, icsLastYOLD)
+	}
+
+	return nil
+}
+
+// holydayEvents returns the events of the holydays of the YOLDs from first to
+// last, and of the first days of the seasons if seasons is set, in order.
+func holydayEvents(first, last int, seasons bool, locale *format.Locale) []icsEvent {
+	var events []icsEvent
+
+	// count the YOLDs rather than compare them, which would never end at the
+	// largest int
+	for i := 0; i < last-first+1; i++ {
+		yold := first + i
+
+		for _, date := range format.Holydays(yold) {
+			// the names in the UIDs are always in English, so that they do
+			// not change with the locale
+			summary, name := locale.TibsDay, "st-tibs-day"
+			if holyday, ok := date.Holyday(); ok {
+				summary, name = locale.Holyday(holyday), slug(holyday.String())
+			}
+
+			events = append(events, icsEvent{
+				uid:     fmt.Sprintf("%d-%s@ddate", yold, name),
+				summary: summary,
+				date:    mustTime(date),
+			})
+		}
+
+		if seasons {
+			for season := format.Chaos; season <= format.TheAftermath; season++ {
+				events = append(events, icsEvent{
+					uid:     fmt.Sprintf("%d-start-of-%s@ddate", yold, slug(season.String())),
+					summary: fmt.Sprintf("%s %d", locale.Season(season), yold),
+					date:    mustTime(format.DateOf(yold, season, 1)),
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].date.Before(events[j].date)
+	})
+
+	return events
+}
+
+// mustTime returns the Gregorian date of a Discordian date which is known to
+// exist.
+func mustTime(date format.Date) time.Time {
+	t, err := date.Time(time.UTC)
+	if err != nil {
+		panic(err)
+	}
+
+	return t
+}
+
+// writeCalendar writes the events as an iCalendar file, as of the given time.
+func writeCalendar(w io.Writer, events []icsEvent, now time.Time) error {
+	var b strings.Builder
+
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:-//norwd//ddate//EN")
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+
+	for _, event := range events {
+		writeLine(&b, "BEGIN:VEVENT")
+		writeLine(&b, "UID:"+escapeText(event.uid))
+		writeLine(&b, "DTSTAMP:"+now.UTC().Format(icsTimeFormat))
+		writeLine(&b, "DTSTART;VALUE=DATE:"+event.date.Format(icsDateFormat))
+		writeLine(&b, "DTEND;VALUE=DATE:"+event.date.AddDate(0, 0, 1).Format(icsDateFormat))
+		writeLine(&b, "SUMMARY:"+escapeText(event.summary))
+		writeLine(&b, "TRANSP:TRANSPARENT")
+		writeLine(&b, "END:VEVENT")
+	}
+
+	writeLine(&b, "END:VCALENDAR")
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// writeLine writes a content line ending in CRLF, folded into lines of at most
+// icsLineLength octets without splitting any UTF-8 character.
+func writeLine(b *strings.Builder, line string) {
+	for limit := icsLineLength; len(line) > limit; limit = icsLineLength - 1 {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+	}
+
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+// icsEscaper escapes the characters of iCalendar text values.
+var icsEscaper = strings.NewReplacer(`\`, `\\`, `;`, `\;`, `,`, `\,`, "\n", `\n`)
+
+// escapeText escapes a text value for iCalendar.
+func escapeText(s string) string {
+	return icsEscaper.Replace(s)
+}
+
+// slug returns the name in lower case, with the words joined by hyphens, and
+// without any other punctuation.
+func slug(name string) string {
+	var words []string
+
+	for _, word := range strings.Fields(name) {
+		word = strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return unicode.ToLower(r)
+			}
+
+			return -1
+		}, word)
+
+		if word != "" {
+			words = append(words, word)
+		}
+	}
+
+	return strings.Join(words, "-")
+}
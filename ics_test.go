@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/norwd/ddate/internal/os"
+)
+
+func TestCalendarFile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string   // name of the test case
+		args   []string // arguments to pass to the command
+		want   []string // expected lines of output, in order
+		events int      // expected number of events
+		exit   int      // expected error code
+	}{
+		{
+			name: "Current YOLD",
+			args: []string{"-d", "2026-10-18"},
+			want: []string{
+				"BEGIN:VCALENDAR",
+				"VERSION:2.0",
+				"BEGIN:VEVENT",
+				"UID:3192-mungday@ddate",
+				"DTSTAMP:20261018T000000Z",
+				"DTSTART;VALUE=DATE:20260105",
+				"DTEND;VALUE=DATE:20260106",
+				"SUMMARY:Mungday",
+				"END:VEVENT",
+				"UID:3192-afflux@ddate",
+				"DTSTART;VALUE=DATE:20261208",
+				"END:VCALENDAR",
+			},
+			events: 10,
+		},
+		{
+			name: "Range With St Tibs Day And Seasons",
+			args: []string{"--seasons", "--locale", "fr", "3165", "3166"},
+			want: []string{
+				"UID:3165-start-of-chaos@ddate",
+				"UID:3165-mungday@ddate",
+				"UID:3166-start-of-chaos@ddate",
+				"UID:3166-chaoflux@ddate",
+				"UID:3166-st-tibs-day@ddate",
+				"DTSTART;VALUE=DATE:20000229",
+				"UID:3166-start-of-discord@ddate",
+				"UID:3166-start-of-the-aftermath@ddate",
+				"DTSTART;VALUE=DATE:20001020",
+			},
+			events: 31,
+		},
+		{
+			name: "Backwards Range",
+			args: []string{"3166", "3165"},
+			want: []string{"ddate: last YOLD 3165 is before first YOLD 3166"},
+			exit: 1,
+		},
+		{
+			name: "Too Many YOLDs",
+			args: []string{"1", "999999999"},
+			want: []string{"ddate: too many YOLDs from 1 to 999999999, at most 100"},
+			exit: 1,
+		},
+		{
+			name: "Largest YOLD",
+			args: []string{"9223372036854775807"},
+			want: []string{"ddate: YOLDs from 9223372036854775807 to 9223372036854775807 out of range, want 1167 to 11165"},
+			exit: 1,
+		},
+		{
+			name: "YOLD Before Gregorian Year 1",
+			args: []string{"1166", "1167"},
+			want: []string{"ddate: YOLDs from 1166 to 1167 out of range, want 1167 to 11165"},
+			exit: 1,
+		},
+		{
+			name: "Invalid YOLD",
+			args: []string{"3166", "MMXXVI"},
+			want: []string{"ddate: invalid YOLD \"MMXXVI\""},
+			exit: 1,
+		},
+	}
+
+	for _, test := range tests {
+		// shadow loop var to prevent nasty bugs
+		test := test
+
+		// trim whitespace from name of test case
+		name := strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+
+			return r
+		}, test.name)
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			var errBuf, outBuf bytes.Buffer
+			var exit int
+
+			defer os.MockAndLockStderr(&errBuf).Unlock()
+			defer os.MockAndLockStdout(&outBuf).Unlock()
+			defer os.MockAndLockEnv(nil).Unlock()
+			defer os.MockAndLockExit(func(code int) {
+				exit = code
+
+				// simulate exit
+				panic("exit")
+			}).Unlock()
+
+			// Act
+			func() {
+				// catch "fake" panic
+				defer func() {
+					if err := recover(); err != nil && err != "exit" {
+						panic(err)
+					}
+				}()
+
+				calendarFile("ddate", append([]string{"--utc"}, test.args...))
+			}()
+
+			// Assert
+			if have, want := exit, test.exit; have != want {
+				t.Fatalf("exit code: have %d, want %d", have, want)
+			}
+
+			out := outBuf.String()
+			if test.exit != 0 {
+				out = strings.ReplaceAll(errBuf.String(), "\n", "\r\n")
+			}
+
+			if !strings.HasSuffix(out, "\r\n") || strings.Contains(strings.ReplaceAll(out, "\r\n", ""), "\n") {
+				t.Fatalf("output: lines not ended with CRLF in:\n%s", out)
+			}
+
+			// every expected line must appear, in order
+			lines := strings.Split(out, "\r\n")
+
+			for _, want := range test.want {
+				for len(lines) > 0 && lines[0] != want {
+					lines = lines[1:]
+				}
+
+				if len(lines) == 0 {
+					t.Fatalf("output: missing line %q in:\n%s", want, out)
+				}
+			}
+
+			if have, want := strings.Count(out, "BEGIN:VEVENT\r\n"), test.events; have != want {
+				t.Errorf("events: have %d, want %d", have, want)
+			}
+		})
+	}
+}
+
+func TestWriteLine(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string // name of the test case
+		line string // content line to write
+		want string // expected output
+	}{
+		{
+			name: "Short",
+			line: "SUMMARY:Mungday",
+			want: "SUMMARY:Mungday\r\n",
+		},
+		{
+			name: "Exactly Full",
+			line: strings.Repeat("x", 75),
+			want: strings.Repeat("x", 75) + "\r\n",
+		},
+		{
+			name: "Folded Twice",
+			line: strings.Repeat("x", 75+74+1),
+			want: strings.Repeat("x", 75) + "\r\n " + strings.Repeat("x", 74) + "\r\n x\r\n",
+		},
+		{
+			name: "Multibyte Character At Fold",
+			line: strings.Repeat("x", 74) + "ß",
+			want: strings.Repeat("x", 74) + "\r\n ß\r\n",
+		},
+	}
+
+	for _, test := range tests {
+		// shadow loop var to prevent nasty bugs
+		test := test
+
+		// trim whitespace from name of test case
+		name := strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+
+			return r
+		}, test.name)
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			var b strings.Builder
+
+			// Act
+			writeLine(&b, test.line)
+
+			// Assert
+			if have, want := b.String(), test.want; have != want {
+				t.Errorf("line: have %q, want %q", have, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+// Command gendoc generates the list of format directives in the package
+// documentation of ddate, and the ddate.1 man page from that documentation, so
+// that both follow the directives of the format package. It is run from the
+// root of the repository by go generate.
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/norwd/ddate/format"
+)
+
+const (
+	// docFile is the package documentation of ddate.
+	docFile = "doc.go"
+
+	// manFile is the man page of ddate.
+	manFile = "ddate.1"
+
+	// directivesIntro is the line of the documentation which is followed by
+	// the list of directives.
+	directivesIntro = "// There are a number of formatting directives available to format the date."
+
+	// lineWidth is the width the list of directives is wrapped at.
+	lineWidth = 80
+
+	// listIndent is the indentation of the items of a list in the documentation.
+	listIndent = "    "
+)
+
+func main() {
+	doc, err := os.ReadFile(docFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gendoc: %s\n", err)
+		os.Exit(1)
+	}
+
+	text, err := rewriteDirectives(string(doc))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gendoc: %s: %s\n", docFile, err)
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(docFile, []byte(text), 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "gendoc: %s\n", err)
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(manFile, []byte(roff(text)), 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "gendoc: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// rewriteDirectives replaces the list of directives in the documentation with
+// the directives of the format package.
+func rewriteDirectives(doc string) (string, error) {
+	lines := strings.Split(doc, "\n")
+
+	start := -1
+	for i, line := range lines {
+		if line == directivesIntro {
+			start = i + 2 // skip the blank line after the introduction
+			break
+		}
+	}
+
+	if start < 0 || start > len(lines) {
+		return "", fmt.Errorf("missing line %q", directivesIntro)
+	}
+
+	end := start
+	for end < len(lines) && strings.HasPrefix(lines[end], "// "+listIndent) {
+		end++
+	}
+
+	var list []string
+	for _, info := range format.Directives() {
+		item := fmt.Sprintf("%s %s", info, info.Summary())
+		if !strings.HasSuffix(item, ".") {
+			item += "."
+		}
+
+		list = append(list, wrap("// "+listIndent+"- ", "// "+listIndent+"  ", item)...)
+	}
+
+	lines = append(lines[:start], append(list, lines[end:]...)...)
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// wrap breaks the text into lines of at most lineWidth, starting with the
+// prefix on the first line and the indent on the others.
+func wrap(prefix, indent, text string) []string {
+	var lines []string
+
+	line := prefix
+	for i, word := range strings.Fields(text) {
+		if i > 0 && len(line)+1+len(word) > lineWidth {
+			lines = append(lines, line)
+			line = indent + word
+		} else if i > 0 {
+			line += " " + word
+		} else {
+			line += word
+		}
+	}
+
+	return append(lines, line)
+}
+
+// roff returns the man page of the package documentation.
+//
+// The sections of the documentation start at the Name heading, headings are
+// lines of a paragraph of their own which are only made of letters, indented
+// lines starting with a hyphen are items of a list of directives, and other
+// indented lines are printed as they are.
+func roff(doc string) string {
+	var out strings.Builder
+
+	out.WriteString(".TH DDATE 1 \"\" \"ddate\" \"User Commands\"\n")
+
+	for _, block := range blocks(doc) {
+		switch {
+		case len(block) == 1 && isHeading(block[0]):
+			fmt.Fprintf(&out, ".SH %s\n", strings.ToUpper(block[0]))
+		case strings.HasPrefix(block[0], listIndent+"- "):
+			for _, item := range items(block) {
+				code, text, _ := strings.Cut(item, " ")
+				fmt.Fprintf(&out, ".TP\n.B %s\n%s\n", escape(code), escape(text))
+			}
+		case strings.HasPrefix(block[0], listIndent):
+			out.WriteString(".PP\n.RS 4\n.nf\n")
+
+			for _, line := range block {
+				fmt.Fprintf(&out, "%s\n", escape(strings.TrimPrefix(line, listIndent)))
+			}
+
+			out.WriteString(".fi\n.RE\n")
+		default:
+			out.WriteString(".PP\n")
+
+			for _, line := range block {
+				fmt.Fprintf(&out, "%s\n", escape(line))
+			}
+		}
+	}
+
+	return out.String()
+}
+
+// blocks returns the paragraphs and indented blocks of the documentation from
+// the Name heading on, as lines without the comment markers.
+func blocks(doc string) [][]string {
+	var blocks [][]string
+	var block []string
+
+	started := false
+
+	for _, line := range strings.Split(doc, "\n") {
+		if !strings.HasPrefix(line, "//") {
+			break
+		}
+
+		line = strings.TrimPrefix(strings.TrimPrefix(line, "//"), " ")
+		started = started || line == "Name"
+
+		// an indented line after a paragraph starts a block of its own
+		indented := strings.HasPrefix(line, listIndent)
+		if len(block) > 0 && indented != strings.HasPrefix(block[0], listIndent) {
+			blocks, block = append(blocks, block), nil
+		}
+
+		switch {
+		case !started:
+			continue
+		case strings.TrimSpace(line) == "" && len(block) > 0:
+			blocks, block = append(blocks, block), nil
+		case strings.TrimSpace(line) != "":
+			block = append(block, line)
+		}
+	}
+
+	if len(block) > 0 {
+		blocks = append(blocks, block)
+	}
+
+	return blocks
+}
+
+// items returns the items of a list, without the hyphens, joined into a line.
+func items(block []string) []string {
+	var items []string
+
+	for _, line := range block {
+		if text := strings.TrimPrefix(line, listIndent+"- "); text != line {
+			items = append(items, text)
+		} else if len(items) > 0 {
+			items[len(items)-1] += " " + strings.TrimSpace(line)
+		}
+	}
+
+	return items
+}
+
+// isHeading reports whether the line of a paragraph of its own is a heading.
+func isHeading(line string) bool {
+	return unicode.IsUpper([]rune(line)[0]) && strings.IndexFunc(line, func(r rune) bool {
+		return !unicode.IsLetter(r) && r != ' '
+	}) < 0
+}
+
+// escape escapes the text for roff.
+func escape(text string) string {
+	text = strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace(text)
+
+	if strings.HasPrefix(text, ".") || strings.HasPrefix(text, "'") {
+		text = `\&` + text
+	}
+
+	return text
+}
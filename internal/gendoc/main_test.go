@@ -0,0 +1,72 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestGenerated(t *testing.T) {
+	t.Parallel()
+
+	doc, err := os.ReadFile("../../" + docFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string                       // name of the test case
+		file     string                       // generated file, relative to the root
+		generate func(string) (string, error) // generates the file from doc.go
+	}{
+		{
+			name:     "Directives In Doc",
+			file:     docFile,
+			generate: rewriteDirectives,
+		},
+		{
+			name: "Man Page",
+			file: manFile,
+			generate: func(doc string) (string, error) {
+				return roff(doc), nil
+			},
+		},
+	}
+
+	for _, test := range tests {
+		// shadow loop var to prevent nasty bugs
+		test := test
+
+		// trim whitespace from name of test case
+		name := strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+
+			return r
+		}, test.name)
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			want, err := os.ReadFile("../../" + test.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Act
+			have, err := test.generate(string(doc))
+
+			// Assert
+			if err != nil {
+				t.Fatalf("error: have %q, want nil", err)
+			}
+
+			if have != string(want) {
+				t.Errorf("%s: out of date, run go generate", test.file)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package os
+
+import (
+	original "os"
+	"sync"
+)
+
+// Getenv retrieves the value of the environment variable named by the key. It
+// returns the value, which will be empty if the variable is not present.
+func Getenv(key string) string {
+	return getenvHook(key)
+}
+
+var getenvHook func(string) string = original.Getenv
+
+// envMutex locks access to changing the getenvHook variable.
+var envMutex sync.Mutex
+
+// MockAndLockEnv mocks the environment variables and returns its unlock hook.
+func MockAndLockEnv(mock map[string]string) interface{ Unlock() } {
+	envMutex.Lock()
+	getenvHook = func(key string) string { return mock[key] }
+	return &envMutex
+}
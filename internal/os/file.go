@@ -0,0 +1,26 @@
+package os
+
+import (
+	"io"
+	"io/fs"
+	original "os"
+	"sync"
+)
+
+// Open opens the named file for reading. If there is an error, it will be of
+// type *fs.PathError.
+func Open(name string) (io.ReadCloser, error) {
+	return openHook(name)
+}
+
+var openHook = func(name string) (io.ReadCloser, error) { return original.Open(name) }
+
+// fsMutex locks access to changing the openHook variable.
+var fsMutex sync.Mutex
+
+// MockAndLockFS mocks the file system and returns its unlock hook.
+func MockAndLockFS(mock fs.FS) interface{ Unlock() } {
+	fsMutex.Lock()
+	openHook = func(name string) (io.ReadCloser, error) { return mock.Open(name) }
+	return &fsMutex
+}
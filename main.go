@@ -3,92 +3,240 @@ package main // import "github.com/norwd/ddate"
 import (
 	"fmt"
 	"path/filepath"
-	"strconv"
 	"strings"
 	"time"
+	"unicode"
+
+	// Embed the time zone database, so that --tz works without one installed.
+	_ "time/tzdata"
+
+	"github.com/norwd/ddate/discordian"
+	"github.com/norwd/ddate/format"
 
 	// This is a mocking wrapper over the "os" package in the standard lib.
 	"github.com/norwd/ddate/internal/os"
 )
 
-// defaultFormat is used if no format is explicitly given.
-const defaultFormat = "%A, %B %d, %Y YOLD"
+// xDayEnv is the environment variable which may hold the date of X-Day.
+const xDayEnv = "DDATE_XDAY"
 
 // backend dependency to preform the date formatting, this allows for injection.
-var backend = func(string, time.Time) (string, error) { panic("backend not defined") }
+var backend = format.Format
 
 // errorf prints the formatted error message to stderr and exits with error 1.
 func errorf(format string, args ...interface{}) {
-	err := fmt.Sprintf(format, args...)
-
-	fmt.Fprintln(os.Stderr, err)
+	warnf(format, args...)
 
 	os.Exit(1)
 }
 
+// warnf prints the formatted error message to stderr without exiting.
+func warnf(format string, args ...interface{}) {
+	fmt.Fprintln(os.Stderr, fmt.Sprintf(format, args...))
+}
+
 // println prints a line to the given output stream.
 func println(line string) {
 	fmt.Fprintln(os.Stdout, line)
 }
 
-// parseDDMMYYYY parses strings representing a day, month, and year as a time.
-//
-// Note that the time returned will normalise the day, month, and year values if
-// they are outside their allowed range. E.g. Oct 32 becomes Nov 1.
-func parseDDMMYYYY(dayStr, monthStr, yearStr string) (t time.Time, err error) {
-	var day, month, year, hour, min, sec, nsec int
+// stringList is a flag value which collects every occurrence of a flag.
+type stringList []string
 
-	if day, err = strconv.Atoi(dayStr); err != nil {
+// String implements the flag.Value interface.
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+// Set implements the flag.Value interface.
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// readFortunes reads the quotes from all of the given fortune files.
+func readFortunes(names []string) (quotes format.Quotes, err error) {
+	for _, name := range names {
+		file, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+
+		more, err := format.ReadFortunes(file)
+		file.Close()
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		quotes = append(quotes, more...)
+	}
+
+	return quotes, nil
+}
+
+func main() {
+	// self is the invocation name.
+	self := filepath.Base(os.Args[0])
+	args := os.Args[1:]
+
+	// Run the command named by the invocation name or the first argument
+	if name, ok := aliases[strings.TrimSuffix(self, ".exe")]; ok {
+		commands[name].run(self, args)
+		return
+	} else if command, ok := commands[firstArg(args)]; ok {
+		command.run(self, args[1:])
 		return
 	}
 
-	if month, err = strconv.Atoi(monthStr); err != nil {
+	printDate(self, args)
+}
+
+// printDate prints the date in the format, which is the mode of ddate when no
+// command is given.
+func printDate(self string, args []string) {
+	// Parse the command line options
+	var s settings
+	var f formatting
+
+	flags := newFlagSet(self, "", mainUsage)
+	flags.Usage = func() { printMainUsage(flags) }
+	s.register(flags)
+	f.register(flags)
+
+	showVersion := flags.Bool("version", false, "print the version and exit")
+	listDirectives := flags.Bool("list-directives", false, "print the format directives and exit")
+
+	reverse := flags.Bool("reverse", false, "convert a Discordian date to a Gregorian date")
+	stdin := flags.Bool("stdin", false, "convert the dates read from stdin, one per line")
+	keepGoing := flags.Bool("keep-going", false, "report bad lines of stdin and carry on")
+
+	var output outputFlag
+	flags.Var(&output, "output", "print dates and errors as `text` or json")
+
+	args, ok := parseFlags(self, flags, args)
+	if !ok {
 		return
 	}
 
-	if year, err = strconv.Atoi(yearStr); err != nil {
+	if *showVersion {
+		println(fmt.Sprintf("%s %s", self, versionString()))
 		return
 	}
 
-	t = time.Date(year, time.Month(month), day, hour, min, sec, nsec, time.Local)
+	if *listDirectives {
+		if err := f.registerDirectives(); err != nil {
+			output.errorf("%s: %s", self, err)
+			return
+		}
+	}
 
-	return
-}
+	if *listDirectives && bool(output) {
+		println(marshal(format.Directives()))
+		return
+	} else if *listDirectives {
+		if err := writeDirectives(os.Stdout); err != nil {
+			output.errorf("%s: %s", self, err)
+		}
 
-func main() {
-	// self is the invocation name.
-	self := filepath.Base(os.Args[0])
-	args := os.Args[1:]
+		return
+	}
+
+	if err := s.resolve(); err != nil {
+		output.errorf("%s: %s", self, err)
+		return
+	}
+
+	// Convert from Discordian to Gregorian instead
+	if *reverse {
+		toGregorian(self, args, s.location, output)
+		return
+	}
+
+	// Collect the formatting options
+	opts, err := f.options(s.locale)
+	if err != nil {
+		output.errorf("%s: %s", self, err)
+		return
+	}
 
 	// Get the default values
-	format, date := defaultFormat, time.Now()
+	layout, date := discordian.DefaultFormat, s.today
+
+	// A lone word is a mistyped command rather than a date
+	if argc := len(args); argc == 1 && isWord(args[0]) {
+		output.errorf("%s: unknown command %q", self, args[0])
+		return
+	}
 
 	// Determine date format
 	if argc := len(args); argc > 0 && strings.HasPrefix(args[0], "+") {
 		// Trim the plus sing from the format
-		format = strings.TrimPrefix(args[0], "+")
+		layout = strings.TrimPrefix(args[0], "+")
 
 		// Reslice arguments to skip the format arguments
 		args = args[1:]
 	}
 
-	// Determine date to use
-	if argc := len(args); argc == 3 {
-		var err error
+	// render converts a date in the format, and in JSON with --output json
+	render := func(date time.Time) (string, error) {
+		formatted, err := backend(layout, date, opts...)
+		if err != nil || !output {
+			return formatted, err
+		}
+
+		return marshal(newDateObject(date, f.xDate, s.locale, formatted)), nil
+	}
+
+	// Read the dates from stdin instead
+	if argc := len(args); argc == 1 && args[0] == "-" {
+		*stdin, args = true, nil
+	}
+
+	if *stdin {
+		if len(args) > 0 || s.date != "" {
+			output.errorf("%s: cannot use both --stdin and a date", self)
+			return
+		}
+
+		batch(self, s.today, render, *keepGoing, output)
+
+		return
+	}
 
-		if date, err = parseDDMMYYYY(args[0], args[1], args[2]); err != nil {
-			errorf("%s: %s", self, err)
+	// Determine date to use
+	if argc := len(args); s.date != "" && argc > 0 {
+		output.errorf("%s: cannot use both --date and DD MM YYYY", self)
+	} else if argc == 3 {
+		if date, err = discordian.ParseDDMMYYYY(args[0], args[1], args[2], s.location); err != nil {
+			output.errorf("%s: %s", self, err)
 		}
 	} else if argc > 3 {
-		errorf("%s: too many arguments for DD MM YYYY", self)
+		output.errorf("%s: too many arguments for DD MM YYYY", self)
 	} else if argc < 3 && argc != 0 {
-		errorf("%s: not enough arguments for DD MM YYYY", self)
+		output.errorf("%s: not enough arguments for DD MM YYYY", self)
 	}
 
 	// Format the date conversion
-	if date, err := backend(format, date); err != nil {
-		errorf("%s: %s", self, err)
+	if date, err := render(date); err != nil {
+		output.errorf("%s: %s", self, err)
 	} else {
 		println(date)
 	}
 }
+
+// firstArg returns the first argument, or an empty string if there is none.
+func firstArg(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+
+	return args[0]
+}
+
+// isWord reports whether s is a single word of letters and hyphens.
+func isWord(s string) bool {
+	return strings.IndexFunc(s, unicode.IsLetter) >= 0 && strings.IndexFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '-'
+	}) < 0
+}
@@ -5,12 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"math"
-	"strconv"
 	"strings"
 	"testing"
+	"testing/fstest"
 	"time"
 	"unicode"
 
+	"github.com/norwd/ddate/discordian"
+	"github.com/norwd/ddate/format"
 	"github.com/norwd/ddate/internal/os"
 )
 
@@ -127,134 +129,22 @@ func TestPrintln(t *testing.T) {
 	}
 }
 
-func TestParseDDMMYYYY(t *testing.T) {
-	t.Parallel()
-
-	tests := []struct {
-		name string    // name of the test case
-		have [3]string // input in DD, MM, and YYYY
-		want [3]int    // expected day, month, and year
-	}{
-		{
-			name: "All Empty",
-			have: [3]string{"", "", ""},
-		},
-		{
-			name: "All Zeros",
-			have: [3]string{"0", "0", "0"},
-			want: [3]int{30, 11, -1},
-		},
-		{
-			name: "Valid Date",
-			have: [3]string{"6", "8", "1999"},
-			want: [3]int{6, 8, 1999},
-		},
-		{
-			name: "Valid Date Leading Zeros",
-			have: [3]string{"06", "08", "01999"},
-			want: [3]int{6, 8, 1999},
-		},
-		{
-			name: "Invalid Day",
-			have: [3]string{"_6", "8", "1999"},
-			want: [3]int{0, 0, 0},
-		},
-		{
-			name: "Invalid Month",
-			have: [3]string{"6", "_8", "1999"},
-			want: [3]int{0, 0, 0},
-		},
-		{
-			name: "Invalid Year",
-			have: [3]string{"6", "8", "_1999"},
-			want: [3]int{0, 0, 0},
-		},
-		{
-			name: "Invalid Date",
-			have: [3]string{"32", "10", "2022"},
-			want: [3]int{1, 11, 2022},
-		},
-	}
-
-	for _, test := range tests {
-		// shadow loop var to prevent nasty bugs
-		test := test
-
-		// trim whitespace from name of test case
-		name := strings.Map(func(r rune) rune {
-			if unicode.IsSpace(r) {
-				return -1
-			}
-
-			return r
-		}, test.name)
-
-		t.Run(name, func(t *testing.T) {
-			t.Parallel()
-
-			// Arrange
-			day, month, year := test.have[0], test.have[1], test.have[2]
-
-			// Act
-			date, err := parseDDMMYYYY(day, month, year)
-
-			// Assert
-			if _, want := strconv.Atoi(day); want != nil {
-				if want := want.Error(); err == nil {
-					t.Fatalf("error: have nil, want %q", want)
-				} else if have := err.Error(); have != want {
-					t.Fatalf("error: have %q, want %q", have, want)
-				}
-
-				return // don't keep testing, expected failure detected
-			} else if have, want := date.Day(), test.want[0]; have != want && err == nil {
-				t.Fatalf("day: have %d, want %d", have, want)
-			}
-
-			if _, want := strconv.Atoi(month); want != nil {
-				if want := want.Error(); err == nil {
-					t.Fatalf("error: have nil, want %q", want)
-				} else if have := err.Error(); have != want {
-					t.Fatalf("error: have %q, want %q", have, want)
-				}
-
-				return // don't keep testing, expected failure detected
-			} else if have, want := int(date.Month()), test.want[1]; have != want && err == nil {
-				t.Fatalf("want: have %d, want %d", have, want)
-			}
-
-			if _, want := strconv.Atoi(year); want != nil {
-				if want := want.Error(); err == nil {
-					t.Fatalf("error: have nil, want %q", want)
-				} else if have := err.Error(); have != want {
-					t.Fatalf("error: have %q, want %q", have, want)
-				}
-
-				return // don't keep testing, expected failure detected
-			} else if have, want := date.Year(), test.want[2]; have != want && err == nil {
-				t.Fatalf("year: have %d, want %d", have, want)
-			}
-
-			if err != nil {
-				t.Fatalf("error: have %q, want nil", err)
-			}
-		})
-	}
-}
-
 func TestMain(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		name        string    // name of the test case
-		self        string    // name of the application
-		args        []string  // arguments to pass to main
-		date        string    // date to return from the backend (if empty expect err)
-		want        string    // expected output
-		ptrn        string    // expected format pattern
-		time        time.Time // expected time to pass to the backend
-		exit        int       // expected error code (signals where output is expected)
-		callBackend bool      // should the backend expect to be called?
+		name        string            // name of the test case
+		self        string            // name of the application
+		args        []string          // arguments to pass to main
+		env         map[string]string // environment variables
+		files       fstest.MapFS      // files to read
+		date        string            // date to return from the backend (if empty expect err)
+		want        string            // expected output
+		ptrn        string            // expected format pattern
+		time        time.Time         // expected time to pass to the backend
+		zone        string            // expected time zone of the time (if not empty)
+		exit        int               // expected error code (signals where output is expected)
+		callBackend bool              // should the backend expect to be called?
 	}{
 		{
 			name:        "No Args",
@@ -262,7 +152,7 @@ func TestMain(t *testing.T) {
 			args:        []string{},
 			date:        "Today's discordian date",
 			want:        "Today's discordian date",
-			ptrn:        defaultFormat,
+			ptrn:        discordian.DefaultFormat,
 			time:        time.Now(),
 			exit:        0,
 			callBackend: true,
@@ -284,11 +174,22 @@ func TestMain(t *testing.T) {
 			args:        []string{"10", "11", "1999"},
 			date:        "The discordian date for 1999-11-10",
 			want:        "The discordian date for 1999-11-10",
-			ptrn:        defaultFormat,
+			ptrn:        discordian.DefaultFormat,
 			time:        time.Date(1999, 11, 10, 0, 0, 0, 0, time.Local),
 			exit:        0,
 			callBackend: true,
 		},
+		{
+			name:        "Only DD MM Negative YYYY",
+			self:        "ddate",
+			args:        []string{"1", "1", "-50"},
+			date:        "The discordian date for -50-01-01",
+			want:        "The discordian date for -50-01-01",
+			ptrn:        discordian.DefaultFormat,
+			time:        time.Date(-50, 1, 1, 0, 0, 0, 0, time.Local),
+			exit:        0,
+			callBackend: true,
+		},
 		{
 			name:        "Format And DD MM YYYY",
 			self:        "ddate",
@@ -306,7 +207,7 @@ func TestMain(t *testing.T) {
 			args:        []string{"Some non-format string"},
 			date:        "",
 			want:        "ddate: not enough arguments for DD MM YYYY",
-			ptrn:        defaultFormat,
+			ptrn:        discordian.DefaultFormat,
 			time:        time.Now(),
 			exit:        1,
 			callBackend: false,
@@ -317,7 +218,7 @@ func TestMain(t *testing.T) {
 			args:        []string{"+Some format string", "10"},
 			date:        "",
 			want:        "ddate: not enough arguments for DD MM YYYY",
-			ptrn:        defaultFormat,
+			ptrn:        discordian.DefaultFormat,
 			time:        time.Now(),
 			exit:        1,
 			callBackend: false,
@@ -328,7 +229,7 @@ func TestMain(t *testing.T) {
 			args:        []string{"+Some format string", "10", "11"},
 			date:        "",
 			want:        "ddate: not enough arguments for DD MM YYYY",
-			ptrn:        defaultFormat,
+			ptrn:        discordian.DefaultFormat,
 			time:        time.Now(),
 			exit:        1,
 			callBackend: false,
@@ -339,7 +240,7 @@ func TestMain(t *testing.T) {
 			args:        []string{"+Some format string", "10", "11", "19", "99"},
 			date:        "",
 			want:        "ddate: too many arguments for DD MM YYYY",
-			ptrn:        defaultFormat,
+			ptrn:        discordian.DefaultFormat,
 			time:        time.Now(),
 			exit:        1,
 			callBackend: false,
@@ -350,7 +251,7 @@ func TestMain(t *testing.T) {
 			args:        []string{"+Some format string", "1_0", "11", "1999"},
 			date:        "",
 			want:        "ddate: strconv.Atoi: parsing \"1_0\": invalid syntax",
-			ptrn:        defaultFormat,
+			ptrn:        discordian.DefaultFormat,
 			time:        time.Now(),
 			exit:        1,
 			callBackend: false,
@@ -361,7 +262,7 @@ func TestMain(t *testing.T) {
 			args:        []string{"+Some format string", "10", "1_1", "1999"},
 			date:        "",
 			want:        "ddate: strconv.Atoi: parsing \"1_1\": invalid syntax",
-			ptrn:        defaultFormat,
+			ptrn:        discordian.DefaultFormat,
 			time:        time.Now(),
 			exit:        1,
 			callBackend: false,
@@ -372,7 +273,470 @@ func TestMain(t *testing.T) {
 			args:        []string{"+Some format string", "10", "11", "19_99"},
 			date:        "",
 			want:        "ddate: strconv.Atoi: parsing \"19_99\": invalid syntax",
-			ptrn:        defaultFormat,
+			ptrn:        discordian.DefaultFormat,
+			time:        time.Now(),
+			exit:        1,
+			callBackend: false,
+		},
+		{
+			name:        "Strict Format",
+			self:        "ddate",
+			args:        []string{"--strict", "+Some fancy format string"},
+			date:        "Today's discordian date",
+			want:        "Today's discordian date",
+			ptrn:        "Some fancy format string",
+			time:        time.Now(),
+			exit:        0,
+			callBackend: true,
+		},
+		{
+			name:        "X-Day Flag",
+			self:        "ddate",
+			args:        []string{"--xday", "1998-07-05", "+%X"},
+			date:        "Days until X-Day",
+			want:        "Days until X-Day",
+			ptrn:        "%X",
+			time:        time.Now(),
+			exit:        0,
+			callBackend: true,
+		},
+		{
+			name:        "Invalid X-Day Flag",
+			self:        "ddate",
+			args:        []string{"--xday", "5 July 1998", "+%X"},
+			date:        "",
+			want:        "ddate: invalid X-Day \"5 July 1998\", want YYYY-MM-DD",
+			ptrn:        "%X",
+			time:        time.Now(),
+			exit:        1,
+			callBackend: false,
+		},
+		{
+			name:        "Invalid X-Day Environment",
+			self:        "ddate",
+			args:        []string{"+%X"},
+			env:         map[string]string{xDayEnv: "tomorrow"},
+			date:        "",
+			want:        "ddate: invalid X-Day \"tomorrow\", want YYYY-MM-DD",
+			ptrn:        "%X",
+			time:        time.Now(),
+			exit:        1,
+			callBackend: false,
+		},
+		{
+			name:        "Fortune Files",
+			self:        "ddate",
+			args:        []string{"--fortune", "eris.txt", "--fortune", "bob.txt", "--daily-quote", "+%."},
+			files:       fstest.MapFS{"eris.txt": {Data: []byte("Hail Eris!\n")}, "bob.txt": {Data: []byte("Praise Bob!\n")}},
+			date:        "Hail Eris!",
+			want:        "Hail Eris!",
+			ptrn:        "%.",
+			time:        time.Now(),
+			exit:        0,
+			callBackend: true,
+		},
+		{
+			name:        "Missing Fortune File",
+			self:        "ddate",
+			args:        []string{"--fortune", "eris.txt", "+%."},
+			date:        "",
+			want:        "ddate: open eris.txt: file does not exist",
+			ptrn:        "%.",
+			time:        time.Now(),
+			exit:        1,
+			callBackend: false,
+		},
+		{
+			name:        "Locale Flag",
+			self:        "ddate",
+			args:        []string{"--locale", "de_DE.UTF-8", "+%A"},
+			date:        "Süßmorgen",
+			want:        "Süßmorgen",
+			ptrn:        "%A",
+			time:        time.Now(),
+			exit:        0,
+			callBackend: true,
+		},
+		{
+			name:        "Unknown Locale Flag",
+			self:        "ddate",
+			args:        []string{"--locale", "tlh", "+%A"},
+			date:        "",
+			want:        "ddate: unknown locale \"tlh\"",
+			ptrn:        "%A",
+			time:        time.Now(),
+			exit:        1,
+			callBackend: false,
+		},
+		{
+			name:        "Unknown Locale Environment",
+			self:        "ddate",
+			args:        []string{"+%A"},
+			env:         map[string]string{"LANG": "tlh"},
+			date:        "Sweetmorn",
+			want:        "Sweetmorn",
+			ptrn:        "%A",
+			time:        time.Now(),
+			exit:        0,
+			callBackend: true,
+		},
+		{
+			name:        "Invalid Locale File",
+			self:        "ddate",
+			args:        []string{"--locale-file", "tlh.json", "+%A"},
+			files:       fstest.MapFS{"tlh.json": {Data: []byte("{}")}},
+			date:        "",
+			want:        "ddate: tlh.json: invalid locale: missing name",
+			ptrn:        "%A",
+			time:        time.Now(),
+			exit:        1,
+			callBackend: false,
+		},
+		{
+			name:        "UTC Flag",
+			self:        "ddate",
+			args:        []string{"--utc", "10", "11", "1999"},
+			date:        "The discordian date for 1999-11-10",
+			want:        "The discordian date for 1999-11-10",
+			ptrn:        discordian.DefaultFormat,
+			time:        time.Date(1999, 11, 10, 0, 0, 0, 0, time.UTC),
+			zone:        "UTC",
+			exit:        0,
+			callBackend: true,
+		},
+		{
+			name:        "Time Zone Flag",
+			self:        "ddate",
+			args:        []string{"--tz", "Asia/Tokyo", "10", "11", "1999"},
+			env:         map[string]string{"TZ": "America/New_York"},
+			date:        "The discordian date for 1999-11-10",
+			want:        "The discordian date for 1999-11-10",
+			ptrn:        discordian.DefaultFormat,
+			time:        time.Date(1999, 11, 9, 15, 0, 0, 0, time.UTC),
+			zone:        "Asia/Tokyo",
+			exit:        0,
+			callBackend: true,
+		},
+		{
+			name:        "Time Zone Environment",
+			self:        "ddate",
+			args:        []string{"10", "11", "1999"},
+			env:         map[string]string{"TZ": ":America/New_York"},
+			date:        "The discordian date for 1999-11-10",
+			want:        "The discordian date for 1999-11-10",
+			ptrn:        discordian.DefaultFormat,
+			time:        time.Date(1999, 11, 10, 5, 0, 0, 0, time.UTC),
+			zone:        "America/New_York",
+			exit:        0,
+			callBackend: true,
+		},
+		{
+			name:        "Invalid Time Zone Environment",
+			self:        "ddate",
+			args:        []string{"10", "11", "1999"},
+			env:         map[string]string{"TZ": "Nowhere/Special"},
+			date:        "The discordian date for 1999-11-10",
+			want:        "The discordian date for 1999-11-10",
+			ptrn:        discordian.DefaultFormat,
+			time:        time.Date(1999, 11, 10, 0, 0, 0, 0, time.UTC),
+			zone:        "UTC",
+			exit:        0,
+			callBackend: true,
+		},
+		{
+			name:        "Invalid Time Zone Flag",
+			self:        "ddate",
+			args:        []string{"--tz", "Nowhere/Special"},
+			date:        "",
+			want:        "ddate: unknown time zone Nowhere/Special",
+			ptrn:        discordian.DefaultFormat,
+			time:        time.Now(),
+			exit:        1,
+			callBackend: false,
+		},
+		{
+			name:        "UTC And Time Zone Flags",
+			self:        "ddate",
+			args:        []string{"--utc", "--tz", "Asia/Tokyo"},
+			date:        "",
+			want:        "ddate: cannot use both --utc and --tz",
+			ptrn:        discordian.DefaultFormat,
+			time:        time.Now(),
+			exit:        1,
+			callBackend: false,
+		},
+		{
+			name:        "Date Flag",
+			self:        "ddate",
+			args:        []string{"--date", "1999-11-10", "+Some fancy format string"},
+			date:        "The discordian date for 1999-11-10",
+			want:        "The discordian date for 1999-11-10",
+			ptrn:        "Some fancy format string",
+			time:        time.Date(1999, 11, 10, 0, 0, 0, 0, time.Local),
+			exit:        0,
+			callBackend: true,
+		},
+		{
+			name:        "Short Date Flag",
+			self:        "ddate",
+			args:        []string{"-d", "tomorrow"},
+			date:        "The discordian date for tomorrow",
+			want:        "The discordian date for tomorrow",
+			ptrn:        discordian.DefaultFormat,
+			time:        time.Now().AddDate(0, 0, 1),
+			exit:        0,
+			callBackend: true,
+		},
+		{
+			name:        "Invalid Date Flag",
+			self:        "ddate",
+			args:        []string{"-d", "someday"},
+			date:        "",
+			want:        "ddate: invalid date \"someday\"",
+			ptrn:        discordian.DefaultFormat,
+			time:        time.Now(),
+			exit:        1,
+			callBackend: false,
+		},
+		{
+			name:        "Date Flag And DD MM YYYY",
+			self:        "ddate",
+			args:        []string{"-d", "today", "10", "11", "1999"},
+			date:        "",
+			want:        "ddate: cannot use both --date and DD MM YYYY",
+			ptrn:        discordian.DefaultFormat,
+			time:        time.Now(),
+			exit:        1,
+			callBackend: false,
+		},
+		{
+			name:        "Convert Command",
+			self:        "ddate",
+			args:        []string{"convert", "Chaos", "5,", "3192"},
+			date:        "",
+			want:        "2026-01-05",
+			ptrn:        discordian.DefaultFormat,
+			time:        time.Now(),
+			exit:        0,
+			callBackend: false,
+		},
+		{
+			name:        "Convert Alias",
+			self:        "dconvert",
+			args:        []string{"--utc", "St. Tib's Day", "3162"},
+			date:        "",
+			want:        "1996-02-29",
+			ptrn:        discordian.DefaultFormat,
+			time:        time.Now(),
+			exit:        0,
+			callBackend: false,
+		},
+		{
+			name:        "Convert Alias On Windows",
+			self:        "dconvert.exe",
+			args:        []string{"Chaos", "1", "3192"},
+			date:        "",
+			want:        "2026-01-01",
+			ptrn:        discordian.DefaultFormat,
+			time:        time.Now(),
+			exit:        0,
+			callBackend: false,
+		},
+		{
+			name:        "Holydays Alias",
+			self:        "dholydays",
+			args:        []string{"3165"},
+			date:        "",
+			want:        "1999-01-05  Chaos 5           Mungday\n1999-02-19  Chaos 50          Chaoflux\n1999-03-19  Discord 5         Mojoday\n1999-05-03  Discord 50        Discoflux\n1999-05-31  Confusion 5       Syaday\n1999-07-15  Confusion 50      Confuflux\n1999-08-12  Bureaucracy 5     Zaraday\n1999-09-26  Bureaucracy 50    Bureflux\n1999-10-24  The Aftermath 5   Maladay\n1999-12-08  The Aftermath 50  Afflux",
+			ptrn:        discordian.DefaultFormat,
+			time:        time.Now(),
+			exit:        0,
+			callBackend: false,
+		},
+		{
+			name:        "Holydays Command With Invalid YOLD",
+			self:        "ddate",
+			args:        []string{"holydays", "MMXXVI"},
+			date:        "",
+			want:        "ddate: invalid YOLD \"MMXXVI\"",
+			ptrn:        discordian.DefaultFormat,
+			time:        time.Now(),
+			exit:        1,
+			callBackend: false,
+		},
+		{
+			name:        "Calendar Alias With Unknown Flag",
+			self:        "dcal",
+			args:        []string{"-x"},
+			date:        "",
+			want:        "dcal: flag provided but not defined: -x",
+			ptrn:        discordian.DefaultFormat,
+			time:        time.Now(),
+			exit:        1,
+			callBackend: false,
+		},
+		{
+			name:        "Unknown Alias",
+			self:        "dfoo",
+			args:        []string{"+Some fancy format string"},
+			date:        "Today's discordian date",
+			want:        "Today's discordian date",
+			ptrn:        "Some fancy format string",
+			time:        time.Now(),
+			exit:        0,
+			callBackend: true,
+		},
+		{
+			name:        "Reverse",
+			self:        "ddate",
+			args:        []string{"--reverse", "Confusion", "23,", "3193"},
+			date:        "",
+			want:        "2027-06-18",
+			ptrn:        discordian.DefaultFormat,
+			time:        time.Now(),
+			exit:        0,
+			callBackend: false,
+		},
+		{
+			name:        "Reverse St Tibs Day",
+			self:        "ddate",
+			args:        []string{"--reverse", "St. Tib's Day, 3162"},
+			date:        "",
+			want:        "1996-02-29",
+			ptrn:        discordian.DefaultFormat,
+			time:        time.Now(),
+			exit:        0,
+			callBackend: false,
+		},
+		{
+			name:        "Reverse St Tibs Day In A Common Year",
+			self:        "ddate",
+			args:        []string{"--reverse", "St. Tib's Day, 3161"},
+			date:        "",
+			want:        "ddate: no St. Tib's Day in 3161 YOLD",
+			ptrn:        discordian.DefaultFormat,
+			time:        time.Now(),
+			exit:        1,
+			callBackend: false,
+		},
+		{
+			name:        "Reverse Without Date",
+			self:        "ddate",
+			args:        []string{"--reverse"},
+			date:        "",
+			want:        "ddate: not enough arguments for Discordian date",
+			ptrn:        discordian.DefaultFormat,
+			time:        time.Now(),
+			exit:        1,
+			callBackend: false,
+		},
+		{
+			name:        "Output JSON",
+			self:        "ddate",
+			args:        []string{"--output", "json", "26", "9", "1995"},
+			date:        "Prickle-Prickle, Bureaucracy 50, 3161 YOLD",
+			want:        `{"gregorian":"1995-09-26","yold":3161,"season":"Bureaucracy","day":50,"weekday":"Prickle-Prickle","holyday":"Bureflux","tibs_day":false,"x_day":2434624,"formatted":"Prickle-Prickle, Bureaucracy 50, 3161 YOLD"}`,
+			ptrn:        discordian.DefaultFormat,
+			time:        time.Date(1995, 9, 26, 0, 0, 0, 0, time.Local),
+			exit:        0,
+			callBackend: true,
+		},
+		{
+			name:        "Output JSON On St Tibs Day",
+			self:        "ddate",
+			args:        []string{"--output=json", "--locale", "de", "--xday", "2000-03-01", "29", "2", "2000"},
+			date:        "Sankt Tibs Tag",
+			want:        `{"gregorian":"2000-02-29","yold":3166,"tibs_day":true,"x_day":1,"formatted":"Sankt Tibs Tag"}`,
+			ptrn:        discordian.DefaultFormat,
+			time:        time.Date(2000, 2, 29, 0, 0, 0, 0, time.Local),
+			exit:        0,
+			callBackend: true,
+		},
+		{
+			name:        "Output JSON Reverse",
+			self:        "ddate",
+			args:        []string{"--output", "json", "--reverse", "Chaos", "9", "3190"},
+			date:        "",
+			want:        `{"gregorian":"2024-01-09"}`,
+			ptrn:        discordian.DefaultFormat,
+			time:        time.Now(),
+			exit:        0,
+			callBackend: false,
+		},
+		{
+			name:        "Output JSON Reverse Failure",
+			self:        "ddate",
+			args:        []string{"--output", "json", "--reverse", "Chaos", "99", "3190"},
+			date:        "",
+			want:        `{"error":"ddate: day 99 out of range for Chaos"}`,
+			ptrn:        discordian.DefaultFormat,
+			time:        time.Now(),
+			exit:        1,
+			callBackend: false,
+		},
+		{
+			name:        "Output JSON Backend Failure",
+			self:        "ddate",
+			args:        []string{"--output", "json", "+%A", "29", "2", "2000"},
+			date:        "",
+			want:        `{"error":"ddate: expected backend error"}`,
+			ptrn:        "%A",
+			time:        time.Date(2000, 2, 29, 0, 0, 0, 0, time.Local),
+			exit:        1,
+			callBackend: true,
+		},
+		{
+			name:        "Unknown Output",
+			self:        "ddate",
+			args:        []string{"--output", "xml"},
+			date:        "",
+			want:        `ddate: invalid value "xml" for flag -output: unknown output "xml", want text or json`,
+			ptrn:        discordian.DefaultFormat,
+			time:        time.Now(),
+			exit:        1,
+			callBackend: false,
+		},
+		{
+			name:        "Options After Arguments",
+			self:        "ddate",
+			args:        []string{"+Some fancy format string", "10", "11", "1999", "--tz", "Asia/Tokyo"},
+			date:        "The discordian date for 1999-11-10",
+			want:        "The discordian date for 1999-11-10",
+			ptrn:        "Some fancy format string",
+			time:        time.Date(1999, 11, 10, 0, 0, 0, 0, time.FixedZone("JST", 9*60*60)),
+			zone:        "Asia/Tokyo",
+			exit:        0,
+			callBackend: true,
+		},
+		{
+			name:        "Arguments After Terminator",
+			self:        "ddate",
+			args:        []string{"--utc", "--", "+%Y", "--tz"},
+			date:        "",
+			want:        "ddate: not enough arguments for DD MM YYYY",
+			ptrn:        discordian.DefaultFormat,
+			time:        time.Now(),
+			exit:        1,
+			callBackend: false,
+		},
+		{
+			name:        "Unknown Command",
+			self:        "ddate",
+			args:        []string{"calendar"},
+			date:        "",
+			want:        "ddate: unknown command \"calendar\"",
+			ptrn:        discordian.DefaultFormat,
+			time:        time.Now(),
+			exit:        1,
+			callBackend: false,
+		},
+		{
+			name:        "Unknown Flag",
+			self:        "ddate",
+			args:        []string{"--bogus"},
+			date:        "",
+			want:        "ddate: flag provided but not defined: -bogus",
+			ptrn:        discordian.DefaultFormat,
 			time:        time.Now(),
 			exit:        1,
 			callBackend: false,
@@ -415,15 +779,17 @@ func TestMain(t *testing.T) {
 			defer os.MockAndLockStderr(&errBuf).Unlock()
 			defer os.MockAndLockStdout(&outBuf).Unlock()
 
-			// mock argv
+			// mock argv and environment
 			defer os.MockAndLockArgs(test.self, test.args).Unlock()
+			defer os.MockAndLockEnv(test.env).Unlock()
+			defer os.MockAndLockFS(test.files).Unlock()
 
 			// mock backend
-			defer mockAndLockBackend(func(format string, date time.Time) (string, error) {
+			defer mockAndLockBackend(func(layout string, date time.Time, _ ...format.Option) (string, error) {
 				backendCalls++
 
 				// check that the format is as expected
-				if have, want := format, test.ptrn; have != want {
+				if have, want := layout, test.ptrn; have != want {
 					t.Errorf("wrong format: have %q, want %q", have, want)
 				}
 
@@ -432,6 +798,11 @@ func TestMain(t *testing.T) {
 					t.Errorf("wrong date: have %s, want %s", have, want)
 				}
 
+				// check that the date is in the expected time zone
+				if have, want := date.Location().String(), test.zone; want != "" && have != want {
+					t.Errorf("wrong time zone: have %s, want %s", have, want)
+				}
+
 				// if the expected date is empty, then an error is expected
 				if test.date == "" {
 					return "", errors.New("expected backend error")
@@ -3,13 +3,15 @@ package main
 import (
 	"sync"
 	"time"
+
+	"github.com/norwd/ddate/format"
 )
 
 // Lock the injectable dependencies only allow sequential access.
 var backendMutex sync.Mutex
 
 // mockAndLockBackend mocks the backend function and returns its unlock hook.
-func mockAndLockBackend(mock func(string, time.Time) (string, error)) interface{ Unlock() } {
+func mockAndLockBackend(mock func(string, time.Time, ...format.Option) (string, error)) interface{ Unlock() } {
 	backendMutex.Lock()
 	backend = mock
 	return &backendMutex
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/norwd/ddate/format"
+	"github.com/norwd/ddate/internal/os"
+)
+
+// maxHolydays is the largest number of holydays listed at once, as many as in
+// the longest range of YOLDs of ics.
+const maxHolydays = 11 * maxYOLDs
+
+// nextUsage describes the arguments of the next command.
+var nextUsage = usage{
+	args:    "[<count>]",
+	summary: "List the next holydays from today, by default only the next one.",
+}
+
+// next prints the next holydays from today, including today, with their
+// Gregorian dates.
+func next(self string, args []string) {
+	var s settings
+
+	flags := newFlagSet(self, "next", nextUsage)
+	s.register(flags)
+
+	args, ok := parseFlags(self, flags, args)
+	if !ok {
+		return
+	}
+
+	if err := s.resolve(); err != nil {
+		errorf("%s: %s", self, err)
+		return
+	}
+
+	count := 1
+
+	switch len(args) {
+	case 0:
+		// list only the next holyday
+	case 1:
+		var err error
+
+		if count, err = strconv.Atoi(args[0]); err != nil || count < 1 {
+			errorf("%s: invalid count %q", self, args[0])
+			return
+		}
+	default:
+		errorf("%s: too many arguments for next", self)
+		return
+	}
+
+	holydays, err := nextHolydays(s.today, count)
+	if err != nil {
+		errorf("%s: %s", self, err)
+		return
+	}
+
+	if err := writeHolydays(os.Stdout, holydays, holydaysFormat, s.location, format.WithLocale(s.locale)); err != nil {
+		errorf("%s: %s", self, err)
+	}
+}
+
+// nextHolydays returns the given number of holydays from the date of today,
+// including today, in order. At most maxHolydays are returned.
+func nextHolydays(today time.Time, count int) ([]format.Date, error) {
+	if count > maxHolydays {
+		return nil, fmt.Errorf("too many holydays %d, at most %d", count, maxHolydays)
+	}
+
+	var holydays []format.Date
+
+	year, month, day := today.Date()
+	midnight := time.Date(year, month, day, 0, 0, 0, 0, today.Location())
+
+	for yold := format.NewDate(today).YOLD; len(holydays) < count; yold++ {
+		for _, holyday := range format.Holydays(yold) {
+			date, err := holyday.Time(today.Location())
+			if err != nil {
+				return nil, err
+			}
+
+			if !date.Before(midnight) && len(holydays) < count {
+				holydays = append(holydays, holyday)
+			}
+		}
+	}
+
+	return holydays, nil
+}
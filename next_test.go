@@ -0,0 +1,88 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+)
+
+func TestNextHolydays(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string    // name of the test case
+		today time.Time // date of today
+		count int       // number of holydays
+		want  []string  // expected holydays
+		err   string    // expected error (if not empty)
+	}{
+		{
+			name:  "Holyday Today",
+			today: time.Date(2026, time.October, 24, 23, 0, 0, 0, time.UTC),
+			count: 1,
+			want:  []string{"Boomtime, The Aftermath 5, 3192 YOLD"},
+		},
+		{
+			name:  "Across YOLDs",
+			today: time.Date(2026, time.December, 9, 0, 0, 0, 0, time.UTC),
+			count: 2,
+			want:  []string{"Setting Orange, Chaos 5, 3193 YOLD", "Setting Orange, Chaos 50, 3193 YOLD"},
+		},
+		{
+			name:  "St Tibs Day",
+			today: time.Date(2028, time.February, 20, 0, 0, 0, 0, time.UTC),
+			count: 2,
+			want:  []string{"St. Tib's Day, 3194 YOLD", "Pungenday, Discord 5, 3194 YOLD"},
+		},
+		{
+			name:  "Too Many Holydays",
+			today: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+			count: 2000000000,
+			err:   "too many holydays 2000000000, at most 1100",
+		},
+	}
+
+	for _, test := range tests {
+		// shadow loop var to prevent nasty bugs
+		test := test
+
+		// trim whitespace from name of test case
+		name := strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+
+			return r
+		}, test.name)
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Act
+			holydays, err := nextHolydays(test.today, test.count)
+
+			// Assert
+			if want := test.err; want != "" {
+				if err == nil {
+					t.Fatalf("error: have nil, want %q", want)
+				} else if have := err.Error(); have != want {
+					t.Fatalf("error: have %q, want %q", have, want)
+				}
+
+				return // don't keep testing, expected failure detected
+			} else if err != nil {
+				t.Fatalf("error: have %q, want nil", err)
+			}
+
+			var have []string
+			for _, holyday := range holydays {
+				have = append(have, holyday.String())
+			}
+
+			if have, want := strings.Join(have, "; "), strings.Join(test.want, "; "); have != want {
+				t.Errorf("holydays: have %q, want %q", have, want)
+			}
+		})
+	}
+}
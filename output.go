@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/norwd/ddate/discordian"
+	"github.com/norwd/ddate/format"
+	"github.com/norwd/ddate/internal/os"
+)
+
+// outputFlag is a flag value which selects either text or JSON output.
+type outputFlag bool
+
+// String implements the flag.Value interface.
+func (o *outputFlag) String() string {
+	if o != nil && *o {
+		return "json"
+	}
+
+	return "text"
+}
+
+// Set implements the flag.Value interface.
+func (o *outputFlag) Set(value string) error {
+	switch value {
+	case "text":
+		*o = false
+	case "json":
+		*o = true
+	default:
+		return fmt.Errorf("unknown output %q, want text or json", value)
+	}
+
+	return nil
+}
+
+// errorf prints the formatted error message like errorf, as a JSON object with
+// JSON output.
+func (o outputFlag) errorf(format string, args ...interface{}) {
+	o.warnf(format, args...)
+
+	os.Exit(1)
+}
+
+// warnf prints the formatted error message like warnf, as a JSON object with
+// JSON output.
+func (o outputFlag) warnf(format string, args ...interface{}) {
+	if !o {
+		warnf(format, args...)
+		return
+	}
+
+	warnf("%s", marshal(errorObject{Error: fmt.Sprintf(format, args...)}))
+}
+
+// dateObject is the JSON output of a converted date. The season, day, and
+// weekday are omitted on St. Tib's Day, and the holyday on other days.
+type dateObject struct {
+	Gregorian string `json:"gregorian"`
+	YOLD      int    `json:"yold"`
+	Season    string `json:"season,omitempty"`
+	Day       int    `json:"day,omitempty"`
+	Weekday   string `json:"weekday,omitempty"`
+	Holyday   string `json:"holyday,omitempty"`
+	TibsDay   bool   `json:"tibs_day"`
+	XDay      int    `json:"x_day"`
+	Formatted string `json:"formatted"`
+}
+
+// gregorianObject is the JSON output of a Discordian date converted back to a
+// Gregorian date.
+type gregorianObject struct {
+	Gregorian string `json:"gregorian"`
+}
+
+// errorObject is the JSON output of an error.
+type errorObject struct {
+	Error string `json:"error"`
+}
+
+// newDateObject returns the JSON object of the converted date t, with the names
+// in the given locale.
+func newDateObject(t, xDay time.Time, locale *format.Locale, formatted string) dateObject {
+	d := format.NewDate(t)
+
+	obj := dateObject{
+		Gregorian: t.Format(discordian.GregorianFormat),
+		YOLD:      d.YOLD,
+		TibsDay:   d.TibsDay,
+		XDay:      format.DaysUntil(t, xDay),
+		Formatted: formatted,
+	}
+
+	if !d.TibsDay {
+		obj.Season = locale.Season(d.Season)
+		obj.Day = d.Day
+		obj.Weekday = locale.Weekday(d.Weekday)
+	}
+
+	if holyday, ok := d.Holyday(); ok {
+		obj.Holyday = locale.Holyday(holyday)
+	}
+
+	return obj
+}
+
+// marshal returns the JSON encoding of v, which cannot fail for the plain
+// structs of the output.
+func marshal(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+
+	return string(data)
+}
@@ -0,0 +1,403 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/norwd/ddate/discordian"
+	"github.com/norwd/ddate/format"
+)
+
+const (
+	// defaultListen is the address the server listens on unless another is
+	// given.
+	defaultListen = "127.0.0.1:8023"
+
+	// readTimeout is how long the server waits for the headers of a request,
+	// and writeTimeout how long it takes at most to answer it.
+	readTimeout, writeTimeout = 10 * time.Second, 30 * time.Second
+)
+
+// serveUsage describes the arguments of the serve command.
+var serveUsage = usage{
+	args:    "",
+	summary: "Answer conversions of dates over HTTP.",
+}
+
+// serve runs an HTTP server which answers conversions of dates, in JSON or in
+// plain text, until it fails.
+func serve(self string, args []string) {
+	var s settings
+	var f formatting
+
+	flags := newFlagSet(self, "serve", serveUsage)
+	s.register(flags)
+	f.register(flags)
+
+	listen := flags.String("listen", defaultListen, "`address` to listen on")
+
+	args, ok := parseFlags(self, flags, args)
+	if !ok {
+		return
+	}
+
+	if len(args) > 0 {
+		errorf("%s: unexpected argument %q", self, args[0])
+		return
+	}
+
+	if err := s.resolve(); err != nil {
+		errorf("%s: %s", self, err)
+		return
+	}
+
+	opts, err := f.options(s.locale)
+	if err != nil {
+		errorf("%s: %s", self, err)
+		return
+	}
+
+	srv := &http.Server{
+		Addr:              *listen,
+		Handler:           newServer(&s, &f, opts),
+		ReadHeaderTimeout: readTimeout,
+		ReadTimeout:       readTimeout,
+		WriteTimeout:      writeTimeout,
+	}
+
+	if err := srv.ListenAndServe(); err != nil {
+		errorf("%s: %s", self, err)
+	}
+}
+
+// server answers the requests of the HTTP API, with the settings and options
+// of the command line as defaults.
+type server struct {
+	settings   *settings
+	formatting *formatting
+	opts       []format.Option
+}
+
+// newServer returns the handler of the HTTP API, which has the endpoints:
+//
+//	/today             today's date
+//	/date/<date>       the date in any form accepted by --date
+//	/reverse/<date>    the Gregorian date of a Discordian date
+//	/holydays[/<YOLD>] the holydays of the current or given YOLD
+//	/holydays.ics      an iCalendar feed of holydays, from and to a YOLD
+//
+// The format, tz, locale, and output query parameters replace the format and
+// the options of the command line.
+func newServer(s *settings, f *formatting, opts []format.Option) http.Handler {
+	srv := &server{settings: s, formatting: f, opts: opts}
+
+	mux := http.NewServeMux()
+	for path, fn := range map[string]endpoint{
+		"/today":        srv.today,
+		"/date/":        srv.date,
+		"/reverse/":     srv.reverse,
+		"/holydays":     srv.holydays,
+		"/holydays/":    srv.holydays,
+		"/holydays.ics": srv.ics,
+	} {
+		mux.Handle(path, srv.handle(path, fn))
+	}
+
+	return mux
+}
+
+// request holds the query parameters of a request.
+type request struct {
+	arg      string         // rest of the path after the endpoint
+	layout   string         // format, empty for the default
+	location *time.Location // time zone of the dates
+	locale   *format.Locale // language of the names
+	json     bool           // answer in JSON instead of plain text
+	query    url.Values     // all query parameters
+}
+
+// response is the answer to a request, as plain text or as a JSON object.
+type response struct {
+	contentType string      // type of the text, if not plain text
+	text        string      // plain text of the answer
+	obj         interface{} // JSON object of the answer
+}
+
+// endpoint answers a request.
+type endpoint func(req *request) (*response, error)
+
+// handle returns the handler of the endpoint at the path, which parses the
+// request, and writes the response of the endpoint, or its error as a bad
+// request.
+func (srv *server) handle(path string, fn endpoint) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		req, err := srv.parseRequest(r, path)
+
+		var resp *response
+		if err == nil {
+			resp, err = fn(req)
+		}
+
+		switch {
+		case err != nil && req.json:
+			writeResponse(w, http.StatusBadRequest, "application/json", marshal(errorObject{Error: err.Error()}))
+		case err != nil:
+			writeResponse(w, http.StatusBadRequest, "text/plain; charset=utf-8", err.Error())
+		case resp.contentType != "":
+			writeResponse(w, http.StatusOK, resp.contentType, resp.text)
+		case req.json:
+			writeResponse(w, http.StatusOK, "application/json", marshal(resp.obj))
+		default:
+			writeResponse(w, http.StatusOK, "text/plain; charset=utf-8", resp.text)
+		}
+	})
+}
+
+// writeResponse writes the body, ending in a newline unless it is empty or
+// already ends in one.
+func writeResponse(w http.ResponseWriter, status int, contentType, body string) {
+	if body != "" && !strings.HasSuffix(body, "\n") {
+		body += "\n"
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(status)
+	fmt.Fprint(w, body)
+}
+
+// parseRequest parses the rest of the path after the endpoint and the query
+// parameters of the request.
+func (srv *server) parseRequest(r *http.Request, path string) (*request, error) {
+	query := r.URL.Query()
+
+	req := &request{
+		arg:      strings.TrimPrefix(r.URL.Path, path),
+		layout:   query.Get("format"),
+		location: srv.settings.location,
+		locale:   srv.settings.locale,
+		query:    query,
+	}
+
+	var output outputFlag
+	if value := query.Get("output"); value != "" {
+		if err := output.Set(value); err != nil {
+			return req, err
+		}
+	}
+
+	req.json = bool(output)
+
+	if tz := query.Get("tz"); tz != "" {
+		location, err := time.LoadLocation(tz)
+		if err != nil {
+			return req, err
+		}
+
+		req.location = location
+	}
+
+	if name := query.Get("locale"); name != "" {
+		locale, err := lookupLocale(name)
+		if err != nil {
+			return req, err
+		}
+
+		req.locale = locale
+	}
+
+	return req, nil
+}
+
+// now returns today in the time zone of the request, or the date given with
+// --date relative to it.
+func (srv *server) now(req *request) (time.Time, error) {
+	now := time.Now().In(req.location)
+
+	if srv.settings.date == "" {
+		return now, nil
+	}
+
+	return discordian.ParseGregorian(srv.settings.date, now)
+}
+
+// options returns the formatting options for the request.
+func (srv *server) options(req *request) []format.Option {
+	opts := make([]format.Option, 0, len(srv.opts)+1)
+	opts = append(opts, srv.opts...)
+
+	return append(opts, format.WithLocale(req.locale))
+}
+
+// convert returns the response of the date in the layout of the request, or
+// in the default layout.
+func (srv *server) convert(req *request, date time.Time, defaultLayout string) (*response, error) {
+	layout := req.layout
+	if layout == "" {
+		layout = defaultLayout
+	}
+
+	formatted, err := backend(layout, date, srv.options(req)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response{
+		text: formatted,
+		obj:  newDateObject(date, srv.formatting.xDate, req.locale, formatted),
+	}, nil
+}
+
+// today answers today's date.
+func (srv *server) today(req *request) (*response, error) {
+	now, err := srv.now(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return srv.convert(req, now, discordian.DefaultFormat)
+}
+
+// date answers the date given in the path.
+func (srv *server) date(req *request) (*response, error) {
+	now, err := srv.now(req)
+	if err != nil {
+		return nil, err
+	}
+
+	date, err := discordian.Parse(req.arg, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return srv.convert(req, date, discordian.DefaultFormat)
+}
+
+// reverse answers the Gregorian date of the Discordian date given in the path,
+// as YYYY-MM-DD in plain text.
+func (srv *server) reverse(req *request) (*response, error) {
+	date, err := discordian.Reverse(req.arg, req.location)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := srv.convert(req, date, discordian.DefaultFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.text = date.Format(discordian.GregorianFormat)
+
+	return resp, nil
+}
+
+// holydays answers the holydays of the YOLD given in the path, or of the
+// current YOLD, as a table in plain text or as a list of JSON objects.
+func (srv *server) holydays(req *request) (*response, error) {
+	yold, err := srv.yold(req, req.arg)
+	if err != nil {
+		return nil, err
+	}
+
+	layout := req.layout
+	if layout == "" {
+		layout = holydaysFormat
+	}
+
+	var text bytes.Buffer
+	if err := writeHolydays(&text, format.Holydays(yold), layout, req.location, srv.options(req)...); err != nil {
+		return nil, err
+	}
+
+	objs := []dateObject{}
+
+	for _, holyday := range format.Holydays(yold) {
+		date, err := holyday.Time(req.location)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := srv.convert(req, date, layout)
+		if err != nil {
+			return nil, err
+		}
+
+		// St. Tib's Day is not a holyday, so it has no name to format
+		obj := resp.obj.(dateObject)
+		obj.Formatted = strings.TrimRight(obj.Formatted, "\t")
+
+		objs = append(objs, obj)
+	}
+
+	return &response{text: text.String(), obj: objs}, nil
+}
+
+// ics answers an iCalendar feed of the holydays of the YOLDs given by the from
+// and to query parameters, by default the current YOLD, and of the first days
+// of the seasons if the seasons query parameter is true.
+func (srv *server) ics(req *request) (*response, error) {
+	first, err := srv.yold(req, firstArg(req.query["from"]))
+	if err != nil {
+		return nil, err
+	}
+
+	last := first
+	if to := firstArg(req.query["to"]); to != "" {
+		if last, err = srv.yold(req, to); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := checkYOLDs(first, last); err != nil {
+		return nil, err
+	}
+
+	var seasons bool
+	if value := firstArg(req.query["seasons"]); value != "" {
+		if seasons, err = strconv.ParseBool(value); err != nil {
+			return nil, fmt.Errorf("invalid seasons %q, want true or false", value)
+		}
+	}
+
+	now, err := srv.now(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var text bytes.Buffer
+	if err := writeCalendar(&text, holydayEvents(first, last, seasons, req.locale), now); err != nil {
+		return nil, err
+	}
+
+	return &response{contentType: "text/calendar; charset=utf-8", text: text.String()}, nil
+}
+
+// yold parses the YOLD, or returns the current YOLD if it is empty.
+func (srv *server) yold(req *request, s string) (int, error) {
+	if s == "" {
+		now, err := srv.now(req)
+		if err != nil {
+			return 0, err
+		}
+
+		return format.NewDate(now).YOLD, nil
+	}
+
+	yold, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid YOLD %q", s)
+	}
+
+	return yold, nil
+}
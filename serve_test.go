@@ -0,0 +1,223 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/norwd/ddate/format"
+	"github.com/norwd/ddate/internal/os"
+)
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string // name of the test case
+		method      string // method of the request
+		path        string // path and query of the request
+		status      int    // expected status code
+		contentType string // expected content type
+		body        string // expected prefix of the body
+	}{
+		{
+			name:        "Today",
+			method:      http.MethodGet,
+			path:        "/today",
+			status:      http.StatusOK,
+			contentType: "text/plain; charset=utf-8",
+			body:        "Sweetmorn, Bureaucracy 72, 3192 YOLD\n",
+		},
+		{
+			name:        "Date In JSON",
+			method:      http.MethodGet,
+			path:        "/date/1995-09-26?output=json&format=%25e+%25B",
+			status:      http.StatusOK,
+			contentType: "application/json",
+			body:        `{"gregorian":"1995-09-26","yold":3161,"season":"Bureaucracy","day":50,"weekday":"Prickle-Prickle","holyday":"Bureflux","tibs_day":false,"x_day":2434624,"formatted":"50th Bureaucracy"}` + "\n",
+		},
+		{
+			name:        "Date With Locale",
+			method:      http.MethodGet,
+			path:        "/date/29%202%202000?locale=de",
+			status:      http.StatusOK,
+			contentType: "text/plain; charset=utf-8",
+			body:        "St. Tibs Tag, 3166 YOLD\n",
+		},
+		{
+			name:        "Date In Another Time Zone",
+			method:      http.MethodGet,
+			path:        "/date/@1792281600?tz=America/Los_Angeles&format=%25Y-%25B-%25d",
+			status:      http.StatusOK,
+			contentType: "text/plain; charset=utf-8",
+			body:        "3192-Bureaucracy-71\n",
+		},
+		{
+			name:        "Reverse",
+			method:      http.MethodGet,
+			path:        "/reverse/Confusion%2023,%203193",
+			status:      http.StatusOK,
+			contentType: "text/plain; charset=utf-8",
+			body:        "2027-06-18\n",
+		},
+		{
+			name:        "Holydays",
+			method:      http.MethodGet,
+			path:        "/holydays/3166",
+			status:      http.StatusOK,
+			contentType: "text/plain; charset=utf-8",
+			body:        "2000-01-05  Chaos 5           Mungday\n2000-02-19  Chaos 50          Chaoflux\n2000-02-29  St. Tib's Day\n2000-03-19  Discord 5         Mojoday\n",
+		},
+		{
+			name:        "Holydays In JSON",
+			method:      http.MethodGet,
+			path:        "/holydays?output=json",
+			status:      http.StatusOK,
+			contentType: "application/json",
+			body:        `[{"gregorian":"2026-01-05","yold":3192,"season":"Chaos","day":5,"weekday":"Setting Orange","holyday":"Mungday","tibs_day":false,"x_day":2423565,"formatted":"Chaos 5\tMungday"},`,
+		},
+		{
+			name:        "ICS Feed",
+			method:      http.MethodGet,
+			path:        "/holydays.ics?from=3192&to=3193&seasons=true",
+			status:      http.StatusOK,
+			contentType: "text/calendar; charset=utf-8",
+			body:        "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		},
+		{
+			name:        "Bad Date",
+			method:      http.MethodGet,
+			path:        "/date/bogus",
+			status:      http.StatusBadRequest,
+			contentType: "text/plain; charset=utf-8",
+			body:        "invalid date \"bogus\"\n",
+		},
+		{
+			name:        "Bad Date In JSON",
+			method:      http.MethodGet,
+			path:        "/reverse/Chaos%2099%203193?output=json",
+			status:      http.StatusBadRequest,
+			contentType: "application/json",
+			body:        `{"error":"day 99 out of range for Chaos"}` + "\n",
+		},
+		{
+			name:        "Unknown Locale",
+			method:      http.MethodGet,
+			path:        "/today?locale=tlh",
+			status:      http.StatusBadRequest,
+			contentType: "text/plain; charset=utf-8",
+			body:        "unknown locale \"tlh\"\n",
+		},
+		{
+			name:        "Backwards Range",
+			method:      http.MethodGet,
+			path:        "/holydays.ics?from=3193&to=3192",
+			status:      http.StatusBadRequest,
+			contentType: "text/plain; charset=utf-8",
+			body:        "last YOLD 3192 is before first YOLD 3193\n",
+		},
+		{
+			name:        "Too Many YOLDs",
+			method:      http.MethodGet,
+			path:        "/holydays.ics?from=1&to=999999999",
+			status:      http.StatusBadRequest,
+			contentType: "text/plain; charset=utf-8",
+			body:        "too many YOLDs from 1 to 999999999, at most 100\n",
+		},
+		{
+			name:        "Largest YOLD",
+			method:      http.MethodGet,
+			path:        "/holydays.ics?from=9223372036854775807",
+			status:      http.StatusBadRequest,
+			contentType: "text/plain; charset=utf-8",
+			body:        "YOLDs from 9223372036854775807 to 9223372036854775807 out of range, want 1167 to 11165\n",
+		},
+		{
+			name:        "Wrong Method",
+			method:      http.MethodPost,
+			path:        "/today",
+			status:      http.StatusMethodNotAllowed,
+			contentType: "text/plain; charset=utf-8",
+			body:        "Method Not Allowed\n",
+		},
+		{
+			name:        "Not Found",
+			method:      http.MethodGet,
+			path:        "/tomorrow",
+			status:      http.StatusNotFound,
+			contentType: "text/plain; charset=utf-8",
+			body:        "404 page not found\n",
+		},
+	}
+
+	for _, test := range tests {
+		// shadow loop var to prevent nasty bugs
+		test := test
+
+		// trim whitespace from name of test case
+		name := strings.Map(func(r rune) rune {
+			if unicode.IsSpace(r) {
+				return -1
+			}
+
+			return r
+		}, test.name)
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Arrange
+			defer os.MockAndLockEnv(nil).Unlock()
+			defer mockAndLockBackend(format.Format).Unlock()
+
+			s := settings{utc: true, date: "2026-10-18"}
+			if err := s.resolve(); err != nil {
+				t.Fatalf("settings: have %q, want nil", err)
+			}
+
+			var f formatting
+
+			opts, err := f.options(s.locale)
+			if err != nil {
+				t.Fatalf("options: have %q, want nil", err)
+			}
+
+			server := httptest.NewServer(newServer(&s, &f, opts))
+			defer server.Close()
+
+			req, err := http.NewRequest(test.method, server.URL+test.path, nil)
+			if err != nil {
+				t.Fatalf("request: have %q, want nil", err)
+			}
+
+			// Act
+			resp, err := server.Client().Do(req)
+			if err != nil {
+				t.Fatalf("response: have %q, want nil", err)
+			}
+
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("body: have %q, want nil", err)
+			}
+
+			// Assert
+			if have, want := resp.StatusCode, test.status; have != want {
+				t.Errorf("status: have %d, want %d", have, want)
+			}
+
+			if have, want := resp.Header.Get("Content-Type"), test.contentType; have != want {
+				t.Errorf("content type: have %q, want %q", have, want)
+			}
+
+			if have, want := string(body), test.body; !strings.HasPrefix(have, want) {
+				t.Errorf("body: have %q, want prefix %q", have, want)
+			}
+		})
+	}
+}
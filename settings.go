@@ -0,0 +1,203 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/norwd/ddate/discordian"
+	"github.com/norwd/ddate/format"
+	"github.com/norwd/ddate/internal/os"
+)
+
+// settings holds the options shared by every mode of ddate.
+type settings struct {
+	utc        bool   // use UTC instead of the local time zone
+	tz         string // name of the time zone
+	localeName string // name of the locale
+	localeFile string // file holding a user-supplied locale
+	date       string // date to use instead of today
+
+	location *time.Location // time zone, once resolved
+	locale   *format.Locale // locale, once resolved
+	today    time.Time      // date to use as today, once resolved
+}
+
+// register defines the flags of the settings in the flag set.
+func (s *settings) register(flags *flag.FlagSet) {
+	flags.BoolVar(&s.utc, "utc", false, "use Coordinated Universal Time")
+	flags.StringVar(&s.tz, "tz", "", "use the time zone `Area/City`, defaults to $TZ")
+	flags.StringVar(&s.localeName, "locale", "", "language of the names, defaults to $LC_ALL, $LC_TIME, or $LANG")
+	flags.StringVar(&s.localeFile, "locale-file", "", "read the names from a JSON locale file")
+	flags.StringVar(&s.date, "date", "", "use the given date instead of today, like date(1)")
+	flags.StringVar(&s.date, "d", "", "shorthand for --date")
+}
+
+// resolve resolves the time zone, locale, and today from the parsed flags.
+func (s *settings) resolve() (err error) {
+	if s.location, err = lookupLocation(s.utc, s.tz); err != nil {
+		return err
+	}
+
+	if s.localeFile != "" {
+		s.locale, err = readLocale(s.localeFile)
+	} else {
+		s.locale, err = lookupLocale(s.localeName)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	s.today = time.Now().In(s.location)
+
+	if s.date != "" {
+		if s.today, err = discordian.ParseGregorian(s.date, s.today); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// formatting holds the options of the modes of ddate which format dates.
+type formatting struct {
+	strict     bool       // fail on directives undefined on St. Tib's Day
+	xDay       string     // date of X-Day as YYYY-MM-DD
+	dailyQuote bool       // select the same quote for the whole day
+	fortunes   stringList // files to read the quotes from
+	directives string     // file to read user-defined directives from
+
+	xDate time.Time // date of X-Day, once resolved
+}
+
+// register defines the flags of the formatting options in the flag set.
+func (f *formatting) register(flags *flag.FlagSet) {
+	flags.BoolVar(&f.strict, "strict", false, "fail on directives undefined on St. Tib's Day")
+	flags.StringVar(&f.xDay, "xday", os.Getenv(xDayEnv), "date of X-Day as YYYY-MM-DD")
+	flags.BoolVar(&f.dailyQuote, "daily-quote", false, "select the same %. quote for the whole day")
+	flags.Var(&f.fortunes, "fortune", "read %. quotes from a fortune file (repeatable)")
+	flags.StringVar(&f.directives, "directives", os.Getenv(directivesEnv), "read user-defined directives from a JSON `file`")
+}
+
+// registerDirectives registers the user-defined directives of the directives
+// file, if any.
+func (f *formatting) registerDirectives() error {
+	if f.directives == "" {
+		return nil
+	}
+
+	return readDirectives(f.directives)
+}
+
+// options resolves the date of X-Day, registers the user-defined directives,
+// and returns the formatting options for the parsed flags, with the names in
+// the given locale.
+func (f *formatting) options(locale *format.Locale) ([]format.Option, error) {
+	opts := []format.Option{format.WithStrictTibsDay(f.strict), format.WithLocale(locale)}
+	f.xDate = format.XDay
+
+	if err := f.registerDirectives(); err != nil {
+		return nil, err
+	}
+
+	if f.xDay != "" {
+		date, err := time.Parse(discordian.GregorianFormat, f.xDay)
+		if err != nil {
+			return nil, fmt.Errorf("invalid X-Day %q, want YYYY-MM-DD", f.xDay)
+		}
+
+		opts = append(opts, format.WithXDay(date))
+		f.xDate = date
+	}
+
+	if len(f.fortunes) > 0 {
+		quotes, err := readFortunes(f.fortunes)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, format.WithQuotes(quotes))
+	}
+
+	if f.dailyQuote {
+		opts = append(opts, format.WithQuoteSelection(format.DailyQuote))
+	}
+
+	return opts, nil
+}
+
+// localeEnv are the environment variables which may name the locale, in order of
+// precedence.
+var localeEnv = []string{"LC_ALL", "LC_TIME", "LANG"}
+
+// lookupLocale returns the built-in locale with the given name. If the name is
+// empty, the locale is named by the environment instead, falling back to English
+// if it is not set or not a built-in locale.
+func lookupLocale(name string) (*format.Locale, error) {
+	if name != "" {
+		if locale, ok := format.LookupLocale(name); ok {
+			return locale, nil
+		}
+
+		return nil, fmt.Errorf("unknown locale %q", name)
+	}
+
+	for _, key := range localeEnv {
+		if value := os.Getenv(key); value != "" {
+			if locale, ok := format.LookupLocale(value); ok {
+				return locale, nil
+			}
+
+			break // only the first variable that is set applies
+		}
+	}
+
+	return format.English, nil
+}
+
+// readLocale reads a user-supplied locale from the given file.
+func readLocale(name string) (*format.Locale, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	locale, err := format.LoadLocale(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return locale, nil
+}
+
+// lookupLocation returns the time zone to use, which is UTC if utc is set, the
+// time zone with the given name if it is not empty, or otherwise the time zone
+// named by the TZ environment variable, falling back to the local time zone if
+// it is not set and to UTC if it is not valid.
+func lookupLocation(utc bool, name string) (*time.Location, error) {
+	switch {
+	case utc && name != "":
+		return nil, errors.New("cannot use both --utc and --tz")
+	case utc:
+		return time.UTC, nil
+	case name != "":
+		return time.LoadLocation(name)
+	}
+
+	tz := os.Getenv("TZ")
+	if tz == "" {
+		return time.Local, nil
+	}
+
+	location, err := time.LoadLocation(strings.TrimPrefix(tz, ":"))
+	if err != nil {
+		return time.UTC, nil
+	}
+
+	return location, nil
+}